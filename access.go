@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/ssh"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// loadAuthorizedUsers reads an authorized_keys file into a set of userKey
+// hashes.
+func loadAuthorizedUsers(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	users := map[string]bool{}
+	for len(data) > 0 {
+		var key gossh.PublicKey
+		key, _, _, data, err = gossh.ParseAuthorizedKey(data)
+		if err != nil {
+			// ParseAuthorizedKey skips lines it can't read, so this
+			// means no key was left.
+			if len(users) == 0 {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			break
+		}
+		users[userKey(key)] = true
+	}
+	return users, nil
+}
+
+// isMember reports whether s authenticated with a member's key: any key
+// when -member-keys isn't set, otherwise only those listed there.
+// Everyone else, including keyless keyboard-interactive clients, is a
+// guest.
+func isMember(s ssh.Session) bool {
+	key := s.PublicKey()
+	return key != nil && (cfg.memberKeys == "" || cfg.memberUsers[userKey(key)])
+}
+
+// modeLabel is the footer line saying which mode the session is in.
+func (m model) modeLabel() string {
+	switch {
+	case m.member && m.store == nil:
+		return "Member · this server saves nothing (no -state-dir)"
+	case m.member:
+		return "Member · your settings and history are saved"
+	}
+	return "Guest · read only, nothing is kept after you leave"
+}
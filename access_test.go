@@ -0,0 +1,87 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/ssh"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+func TestLoadAuthorizedUsers(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(rand.Reader)
+	key, err := gossh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "authorized_keys")
+	data := "# editors\n" + string(gossh.MarshalAuthorizedKey(key)) + "\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	users, err := loadAuthorizedUsers(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 1 || !users[userKey(key)] {
+		t.Errorf("users = %v", users)
+	}
+
+	if err := os.WriteFile(path, []byte("# nobody\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadAuthorizedUsers(path); err == nil {
+		t.Error("empty file: got nil error")
+	}
+}
+
+// fakeSession is just enough of a session for isMember.
+type fakeSession struct {
+	ssh.Session
+	key ssh.PublicKey
+}
+
+func (s fakeSession) PublicKey() ssh.PublicKey { return s.key }
+
+func TestMemberMode(t *testing.T) {
+	newKey := func() ssh.PublicKey {
+		pub, _, _ := ed25519.GenerateKey(rand.Reader)
+		key, err := gossh.NewPublicKey(pub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key
+	}
+	listed, unlisted := newKey(), newKey()
+	session := func(key ssh.PublicKey) fakeSession { return fakeSession{key: key} }
+
+	c := cfg
+	withConfig(t, c)
+	if !isMember(session(unlisted)) || isMember(session(nil)) {
+		t.Error("without -member-keys, any key should make a member and no key a guest")
+	}
+	cfg.memberKeys = "members"
+	cfg.memberUsers = map[string]bool{userKey(listed): true}
+	if !isMember(session(listed)) || isMember(session(unlisted)) {
+		t.Error("with -member-keys, only listed keys should make members")
+	}
+
+	var m tea.Model = newModel(testFeed(), userState{}, 80, 24)
+	if v := m.View(); !strings.Contains(v, "Guest") {
+		t.Errorf("footer doesn't show guest mode:\n%s", v)
+	}
+	mm := m.(model)
+	mm.member = true
+	if v := mm.View(); !strings.Contains(v, "Member · this server saves nothing") {
+		t.Errorf("footer doesn't show member mode without -state-dir:\n%s", v)
+	}
+	mm.store = &stateStore{dir: t.TempDir()}
+	if v := mm.View(); !strings.Contains(v, "Member · your settings and history are saved") {
+		t.Errorf("footer doesn't show member mode:\n%s", v)
+	}
+}
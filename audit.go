@@ -0,0 +1,161 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// audit records who connected and what they opened; nil when -audit-log
+// is not set.
+var audit *auditLog
+
+// auditEvent is one line of the audit log.
+type auditEvent struct {
+	Time  time.Time `json:"time"`
+	Event string    `json:"event"`
+	// User is the userKey hash, as in the state directory; empty for
+	// sessions without a public key.
+	User        string `json:"user,omitempty"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	Remote      string `json:"remote,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Link        string `json:"link,omitempty"`
+	// Prev is the sha256 of the previous line, so deleting or editing a
+	// line breaks the chain from there on. It is only tamper-evident
+	// against someone who doesn't bother to rewrite the rest of the file.
+	Prev string `json:"prev"`
+}
+
+// auditLog appends JSON lines to path, moving it aside to path.<time>
+// once it would grow past maxSize. The hash chain carries over into the
+// new file.
+type auditLog struct {
+	path    string
+	maxSize int64
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+	prev string
+}
+
+func newAuditLog(path string, maxSize int64) (*auditLog, error) {
+	a := &auditLog{path: path, maxSize: maxSize}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if last := lastLine(data); last != nil {
+		a.prev = lineHash(last)
+	}
+	if err := a.open(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// open must be called with mu held, or before a is shared.
+func (a *auditLog) open() error {
+	f, err := os.OpenFile(a.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	a.f, a.size = f, fi.Size()
+	return nil
+}
+
+// rotate must be called with mu held. If the old file can't be moved
+// aside we carry on appending to it rather than lose events.
+func (a *auditLog) rotate(now time.Time) error {
+	if err := a.f.Close(); err != nil {
+		return err
+	}
+	renameErr := os.Rename(a.path, a.path+"."+now.UTC().Format("20060102T150405.000"))
+	if err := a.open(); err != nil {
+		return err
+	}
+	return renameErr
+}
+
+// record appends e, filling in its time and chain hash. Failures are
+// logged rather than returned: an audit hiccup shouldn't end a session.
+func (a *auditLog) record(e auditEvent) {
+	if a == nil {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	e.Time = time.Now()
+	e.Prev = a.prev
+	line, err := json.Marshal(e)
+	if err != nil {
+		log.Error("Failed to encode audit event", "error", err)
+		return
+	}
+	if a.size > 0 && a.size+int64(len(line))+1 > a.maxSize {
+		if err := a.rotate(e.Time); err != nil {
+			log.Error("Failed to rotate audit log", "error", err)
+		}
+	}
+	n, err := a.f.Write(append(line, '\n'))
+	a.size += int64(n)
+	if err != nil {
+		log.Error("Failed to write audit log", "error", err)
+		return
+	}
+	a.prev = lineHash(line)
+}
+
+func (a *auditLog) Close() error {
+	if a == nil {
+		return nil
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.f.Close()
+}
+
+func lineHash(line []byte) string {
+	sum := sha256.Sum256(line)
+	return hex.EncodeToString(sum[:])
+}
+
+// lastLine returns the final line of data without its newline, or nil.
+func lastLine(data []byte) []byte {
+	data = bytes.TrimRight(data, "\n")
+	if len(data) == 0 {
+		return nil
+	}
+	return data[bytes.LastIndexByte(data, '\n')+1:]
+}
+
+// auditMiddleware records each session's start and end.
+func auditMiddleware() func(ssh.Handler) ssh.Handler {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			e := auditEvent{User: userKey(s.PublicKey()), Remote: s.RemoteAddr().String()}
+			if key := s.PublicKey(); key != nil {
+				e.Fingerprint = gossh.FingerprintSHA256(key)
+			}
+			e.Event = "connect"
+			audit.record(e)
+			next(s)
+			e.Event = "disconnect"
+			audit.record(e)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAuditLog(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.jsonl")
+	a, err := newAuditLog(path, 400)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.record(auditEvent{Event: "connect", User: "abc", Fingerprint: "SHA256:xyz"})
+	a.record(auditEvent{Event: "open", User: "abc", Title: "Senate passes budget", Link: "https://example.com/budget"})
+	a.Close()
+
+	// Reopening picks the chain up where it left off, and the third event
+	// no longer fits, so it starts a new file.
+	if a, err = newAuditLog(path, 400); err != nil {
+		t.Fatal(err)
+	}
+	a.record(auditEvent{Event: "disconnect", User: "abc", Fingerprint: "SHA256:xyz"})
+	a.Close()
+
+	files, _ := filepath.Glob(path + "*")
+	if len(files) != 2 {
+		t.Fatalf("got files %v, want the log and one rotated copy", files)
+	}
+	rotated, _ := os.ReadFile(files[1])
+	if files[1] == path {
+		rotated, _ = os.ReadFile(files[0])
+	}
+	current, _ := os.ReadFile(path)
+
+	var prev string
+	var events []string
+	for _, line := range bytes.Split(bytes.TrimSpace(append(rotated, current...)), []byte("\n")) {
+		var e auditEvent
+		if err := json.Unmarshal(line, &e); err != nil {
+			t.Fatal(err)
+		}
+		if e.Prev != prev {
+			t.Errorf("%s event: prev = %q, want %q", e.Event, e.Prev, prev)
+		}
+		prev = lineHash(line)
+		events = append(events, e.Event)
+	}
+	if len(events) != 3 || events[2] != "disconnect" {
+		t.Errorf("events = %v", events)
+	}
+}
+
+func TestAuditLogNil(t *testing.T) {
+	var a *auditLog
+	a.record(auditEvent{Event: "connect"})
+	if err := a.Close(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// feedCache holds the one copy of the feed that every session reads, and
+// keeps it fresh in the background.
+type feedCache struct {
+	url string
+	ttl time.Duration
+	// fallbackURL, if set, is served when url keeps failing.
+	fallbackURL string
+	// followFeedTTL refreshes at the feed's own <ttl>, when it has one,
+	// instead of ttl.
+	followFeedTTL bool
+
+	// fetchMu serializes upstream requests, so sessions arriving at a cold
+	// cache wait for one fetch instead of each making their own.
+	fetchMu sync.Mutex
+
+	mu      sync.RWMutex
+	feed    RSSFeed
+	fetched time.Time
+	// fallback is set while feed came from fallbackURL.
+	fallback bool
+	// edits maps items that changed between refreshes to their previous
+	// version; see trackEdits. It is replaced, never modified, so readers
+	// can keep the map they were given.
+	edits map[string]RSSItem
+	// requested is set once anyone has asked for the feed; until then (in
+	// lazy mode) there is nothing worth refreshing.
+	requested bool
+}
+
+func newFeedCache(url string, ttl time.Duration) *feedCache {
+	return &feedCache{url: url, ttl: ttl}
+}
+
+// peek returns the cached feed without ever blocking on the network.
+func (c *feedCache) peek() (RSSFeed, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.feed, !c.fetched.IsZero()
+}
+
+// get returns the cached feed, fetching it first if the cache is cold.
+func (c *feedCache) get() (RSSFeed, error) {
+	c.mu.Lock()
+	c.requested = true
+	c.mu.Unlock()
+	if feed, ok := c.peek(); ok {
+		return feed, nil
+	}
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+	if feed, ok := c.peek(); ok {
+		return feed, nil
+	}
+	return c.fetch()
+}
+
+// refresh refetches the feed. On failure, or when fetches are being
+// throttled, the stale copy stays in place.
+func (c *feedCache) refresh() {
+	c.mu.RLock()
+	requested := c.requested
+	c.mu.RUnlock()
+	if !requested {
+		return
+	}
+	if !fetchLimit.available() {
+		log.Warn("Skipping feed refresh, fetch rate limit reached")
+		return
+	}
+	c.fetchMu.Lock()
+	defer c.fetchMu.Unlock()
+	if _, err := c.fetch(); err != nil {
+		log.Warn("Failed to refresh feed, serving stale copy", "error", err)
+	}
+}
+
+// recentEdits returns the previous versions of items that have changed.
+func (c *feedCache) recentEdits() map[string]RSSItem {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.edits
+}
+
+// fallbackAttempts is how many times the primary feed is tried before
+// giving up on it for the fallback, retryDelay apart.
+const fallbackAttempts = 3
+
+var retryDelay = 500 * time.Millisecond
+
+// onFallback reports whether the cached feed is the fallback one.
+func (c *feedCache) onFallback() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.fallback
+}
+
+// fetch must be called with fetchMu held. The primary feed is always
+// tried first, so a recovered primary replaces the fallback straight away.
+func (c *feedCache) fetch() (RSSFeed, error) {
+	feed, err := scrapeUrlFeed(c.url)
+	fallback := false
+	if err != nil && c.fallbackURL != "" {
+		for i := 1; i < fallbackAttempts && err != nil; i++ {
+			time.Sleep(retryDelay)
+			feed, err = scrapeUrlFeed(c.url)
+		}
+		if err != nil {
+			log.Warn("Primary feed failed, switching to fallback", "error", err)
+			feed, err = scrapeUrlFeed(c.fallbackURL)
+			fallback = true
+		}
+	}
+	if err != nil {
+		return RSSFeed{}, err
+	}
+	c.mu.Lock()
+	if c.fallback && !fallback {
+		log.Info("Primary feed is back, leaving fallback")
+	}
+	if fallback == c.fallback {
+		c.edits = trackEdits(c.feed.Items, feed.Items, c.edits)
+	} else {
+		c.edits = nil
+	}
+	c.feed, c.fetched, c.fallback = feed, time.Now(), fallback
+	c.mu.Unlock()
+	return feed, nil
+}
+
+// minFeedTTL is the shortest <ttl> we honour, so a feed can't have us
+// polling it every minute.
+const minFeedTTL = 5 * time.Minute
+
+// interval is how long to wait between refreshes.
+func (c *feedCache) interval() time.Duration {
+	if !c.followFeedTTL {
+		return c.ttl
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if ttl := c.feed.ttl(); ttl > 0 {
+		return max(ttl, minFeedTTL)
+	}
+	return c.ttl
+}
+
+// run refreshes the cache until ctx is done, waiting interval between
+// refreshes so a change in the feed's <ttl> applies from the next one.
+func (c *feedCache) run(ctx context.Context) {
+	t := time.NewTimer(c.interval())
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			c.refresh()
+			t.Reset(c.interval())
+		}
+	}
+}
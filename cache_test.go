@@ -0,0 +1,116 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFeedCache(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "feed.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var requests atomic.Int32
+	var failing atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if failing.Load() {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write(data)
+	}))
+	defer srv.Close()
+
+	c := newFeedCache(srv.URL, time.Hour)
+	c.refresh()
+	if _, ok := c.peek(); ok || requests.Load() != 0 {
+		t.Fatal("refreshed a feed nobody asked for")
+	}
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := c.get(); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if n := requests.Load(); n != 1 {
+		t.Errorf("got %d upstream requests for concurrent sessions, want 1", n)
+	}
+
+	c.refresh()
+	if n := requests.Load(); n != 2 {
+		t.Errorf("got %d upstream requests after a refresh, want 2", n)
+	}
+
+	if got := c.interval(); got != time.Hour {
+		t.Errorf("interval = %s, want the configured hour", got)
+	}
+	c.followFeedTTL = true
+	if got := c.interval(); got != minFeedTTL {
+		t.Errorf("interval for a 1m <ttl> = %s, want the %s floor", got, minFeedTTL)
+	}
+
+	// A failed refresh leaves the stale copy in place.
+	failing.Store(true)
+	c.refresh()
+	feed, ok := c.peek()
+	if !ok || feed.Title != "Test Playbook" {
+		t.Errorf("got %q, %v; want the stale feed", feed.Title, ok)
+	}
+}
+
+func TestFeedCacheFallback(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "feed.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var attempts atomic.Int32
+	var failing atomic.Bool
+	failing.Store(true)
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		if failing.Load() {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write(data)
+	}))
+	defer primary.Close()
+	backup := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(`<rss><channel><title>Backup</title></channel></rss>`))
+	}))
+	defer backup.Close()
+	defer func(d time.Duration) { retryDelay = d }(retryDelay)
+	retryDelay = 0
+
+	c := newFeedCache(primary.URL, time.Hour)
+	c.fallbackURL = backup.URL
+	feed, err := c.get()
+	if err != nil || feed.Title != "Backup" || !c.onFallback() {
+		t.Fatalf("got %q, %v, fallback %v; want the backup feed", feed.Title, err, c.onFallback())
+	}
+	if n := attempts.Load(); n != fallbackAttempts {
+		t.Errorf("primary tried %d times, want %d", n, fallbackAttempts)
+	}
+
+	failing.Store(false)
+	c.refresh()
+	if feed, _ := c.peek(); feed.Title != "Test Playbook" || c.onFallback() {
+		t.Errorf("got %q, fallback %v; want the primary back", feed.Title, c.onFallback())
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+)
+
+// maxSlugLen keeps clip file names well short of any filesystem limit.
+const maxSlugLen = 60
+
+// slug turns a title into a file name part: lower case ASCII letters and
+// digits, everything else collapsed into single dashes.
+func slug(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if ('a' <= r && r <= 'z') || ('0' <= r && r <= '9') {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(r)
+			continue
+		}
+		dash = true
+	}
+	out := b.String()
+	if len(out) > maxSlugLen {
+		out = strings.TrimRight(out[:maxSlugLen], "-")
+	}
+	if out == "" {
+		return "article"
+	}
+	return out
+}
+
+// clipName is the file name for item: its publication date, or today's
+// if it has none, then its title's slug.
+func clipName(item rssListItem, now time.Time) string {
+	date := item.published
+	if date.IsZero() {
+		date = now
+	}
+	return date.UTC().Format("2006-01-02") + "-" + slug(item.title)
+}
+
+// clipArticle saves the selected article's markdown to -clip-dir, next
+// to, never over, any earlier clip of the same name. Only sessions whose
+// key is in -clip-keys may, since it writes to the server's disk.
+func (m *model) clipArticle() tea.Cmd {
+	if !m.canClip || cfg.clipDir == "" {
+		return m.list.NewStatusMessage("Saving articles isn't enabled for this session")
+	}
+	item, ok := m.current()
+	if !ok {
+		return m.noSelection()
+	}
+	// detailMarkdown renders m.selected, so render a copy for item.
+	c := *m
+	c.selected = item
+	data := []byte(c.detailMarkdown() + "\n")
+	if err := os.MkdirAll(cfg.clipDir, 0o700); err != nil {
+		log.Error("Failed to save article", "error", err)
+		return m.list.NewStatusMessage("Saving failed")
+	}
+	base := clipName(item, time.Now())
+	for n := 1; ; n++ {
+		name := base + ".md"
+		if n > 1 {
+			name = fmt.Sprintf("%s-%d.md", base, n)
+		}
+		path := filepath.Join(cfg.clipDir, name)
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err == nil {
+			_, err = f.Write(data)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}
+		if err != nil {
+			log.Error("Failed to save article", "error", err)
+			return m.list.NewStatusMessage("Saving failed")
+		}
+		return m.list.NewStatusMessage("Saved to " + path)
+	}
+}
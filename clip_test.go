@@ -0,0 +1,72 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSlug(t *testing.T) {
+	tests := []struct{ title, want string }{
+		{"Senate passes budget", "senate-passes-budget"},
+		{"  ../../etc/passwd ", "etc-passwd"},
+		{"Q&A: what's next?", "q-a-what-s-next"},
+		{"Élection 2025", "lection-2025"},
+		{"¿?", "article"},
+	}
+	for _, tt := range tests {
+		if got := slug(tt.title); got != tt.want {
+			t.Errorf("slug(%q) = %q, want %q", tt.title, got, tt.want)
+		}
+	}
+	if got := slug(strings.Repeat("ab ", 40)); len(got) > maxSlugLen || strings.HasSuffix(got, "-") {
+		t.Errorf("long slug = %q", got)
+	}
+}
+
+func TestClipArticle(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "clips")
+	c := cfg
+	c.clipDir = dir
+	withConfig(t, c)
+
+	feed := testFeed()
+	feed.Items[0].PublishDate = "Mon, 02 Jun 2025 09:30:00 +0000"
+	var m tea.Model = newModel(feed, userState{}, 80, 40)
+	m, _ = m.Update(keyMsg("s"))
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Fatalf("an untrusted session saved an article: %v", err)
+	}
+
+	tm := m.(model)
+	tm.canClip = true
+	m = tm
+	m, _ = m.Update(keyMsg("s"))
+	m, _ = m.Update(keyMsg("s"))
+	want := []string{"2025-06-02-senate-passes-budget-2.md", "2025-06-02-senate-passes-budget.md"}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d files, want %d", len(entries), len(want))
+	}
+	for i, e := range entries {
+		if e.Name() != want[i] {
+			t.Errorf("file %d = %q, want %q", i, e.Name(), want[i])
+		}
+	}
+	data, err := os.ReadFile(filepath.Join(dir, want[1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "# Senate passes budget\n") {
+		t.Errorf("saved %q", data)
+	}
+	if v := m.View(); !strings.Contains(v, "Saved to") {
+		t.Errorf("no confirmation in view:\n%s", v)
+	}
+}
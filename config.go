@@ -0,0 +1,447 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+)
+
+// config holds the operator-tunable settings. It is populated from flags
+// once in main and read-only afterwards.
+type config struct {
+	feedURL string
+	// fallbackFeed is served, with a banner, while feedURL is failing.
+	fallbackFeed string
+	// connectTimeout bounds dialing and the TLS handshake with the feed host.
+	connectTimeout time.Duration
+	// readTimeout bounds how long to wait for response headers once connected.
+	readTimeout time.Duration
+	// fetchTimeout bounds a whole fetch, body included. A body arriving
+	// slower than minFetchRate bytes a second over minRateWindow is
+	// abandoned sooner; 0 disables that check.
+	fetchTimeout  time.Duration
+	minFetchRate  float64
+	minRateWindow time.Duration
+	// maxFeedSize caps a feed's body, so a host can't make every fetch
+	// buffer however much it likes.
+	maxFeedSize int64
+	// fetchMode is fetchLazy or fetchEager; see the -fetch usage.
+	fetchMode string
+	// cacheTTL is how often the shared feed is refreshed.
+	cacheTTL time.Duration
+	// fetchRate caps upstream feed requests per minute, 0 for no limit,
+	// allowing fetchBurst at once.
+	fetchRate  float64
+	fetchBurst int
+	// sanitize maps a feed URL to the name of its sanitizePolicies entry.
+	sanitize perFeed
+	// include and exclude are per-feed regular expressions items must or
+	// must not match, compiled into filters by validate; filterOn says
+	// whether they see just the title or the description too.
+	include  perFeed
+	exclude  perFeed
+	filterOn string
+	filters  map[string]itemFilter
+	// pins maps a feed URL to the SPKI hashes its host must present; see
+	// parsePins.
+	pins perFeed
+	// motd is shown above the list; motdURL, if set, replaces it with a
+	// remote message refetched every motdRefresh.
+	motd        string
+	motdURL     string
+	motdRefresh time.Duration
+	// dwell, if positive, auto-advances the detail view to the next
+	// article after that long.
+	dwell time.Duration
+	// pauseOnBlur asks clients for focus events and stops timers while
+	// their terminal is in the background.
+	pauseOnBlur bool
+	// linkPreview fetches each opened article's page for its Open Graph
+	// summary.
+	linkPreview bool
+	// sort is the -sort flag as given, parsed into sortKeys by validate.
+	sort     string
+	sortKeys []string
+	// linkSchemes are the URL schemes feed links may have; see safeLink.
+	linkSchemes schemeList
+	// Descriptions over maxDescSize bytes or nested deeper than
+	// maxDescDepth are shown as plain text; see flattenHeavyDescriptions.
+	maxDescSize  int
+	maxDescDepth int
+	// Items dated more than futureSlack ahead of the server's clock, or
+	// longer than maxItemAge ago, are flagged; see dateWarning.
+	futureSlack time.Duration
+	maxItemAge  time.Duration
+	// splash is shown while the first fetch runs, and for at least
+	// splashTime; validate replaces it with splashFile's contents, if set.
+	splash     string
+	splashFile string
+	splashTime time.Duration
+	// hideTitleDescriptions blanks list descriptions that only repeat the
+	// item's title.
+	hideTitleDescriptions bool
+	// netrc sends Basic auth to feed hosts listed in $NETRC or ~/.netrc.
+	netrc bool
+	// stateDir holds per-user preferences; empty disables persistence.
+	stateDir string
+	// exportDir receives history exports; when empty they go to the
+	// client's clipboard instead.
+	exportDir string
+	// publicHost is the host, or host:port, users ssh to, for sharing.
+	publicHost string
+	// memberKeys is an authorized_keys file of the members whose settings
+	// and history are kept; when empty any key makes a member. validate
+	// loads it into memberUsers.
+	memberKeys  string
+	memberUsers map[string]bool
+	// clipDir receives articles saved with s, by sessions whose key is in
+	// the clipKeys authorized_keys file; validate loads it into clipUsers.
+	clipDir   string
+	clipKeys  string
+	clipUsers map[string]bool
+	// agentNames greets users by the comment on their key when they forward
+	// an agent.
+	agentNames bool
+	// hostKeyDir, when set, holds every host key to offer instead of the
+	// single generated one; primaryHostKey names the file that wins a tie.
+	hostKeyDir     string
+	primaryHostKey string
+	// auditLog is where connections and article opens are recorded, empty
+	// to keep no audit trail; it is rotated past auditMaxSize MB.
+	auditLog     string
+	auditMaxSize int64
+	// Every opened article is POSTed to openHookURL, or passed to the
+	// openHookExec command, giving up after openHookTimeout; see hookRunner.
+	openHookURL     string
+	openHookExec    string
+	openHookTimeout time.Duration
+	// versionURL, if set, is polled every versionCheck for a newer build.
+	versionURL   string
+	versionCheck time.Duration
+	// debug enables developer views such as the raw item XML.
+	debug bool
+}
+
+var cfg = config{
+	feedURL:               defaultFeedURL,
+	publicHost:            "politics.news",
+	linkSchemes:           schemeList{"http", "https", "mailto"},
+	maxDescSize:           64 << 10,
+	maxDescDepth:          32,
+	splash:                defaultSplash,
+	futureSlack:           15 * time.Minute,
+	maxItemAge:            365 * 24 * time.Hour,
+	connectTimeout:        2 * time.Second,
+	readTimeout:           2 * time.Second,
+	fetchTimeout:          10 * time.Second,
+	minFetchRate:          512,
+	minRateWindow:         5 * time.Second,
+	maxFeedSize:           10 << 20,
+	fetchMode:             fetchLazy,
+	cacheTTL:              5 * time.Minute,
+	fetchBurst:            1,
+	sanitize:              perFeed{},
+	pins:                  perFeed{},
+	include:               perFeed{},
+	exclude:               perFeed{},
+	filterOn:              filterOnTitle,
+	motdRefresh:           5 * time.Minute,
+	hideTitleDescriptions: true,
+	auditMaxSize:          100,
+	versionCheck:          24 * time.Hour,
+	openHookTimeout:       5 * time.Second,
+}
+
+func (c *config) registerFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.feedURL, "feed", c.feedURL, "URL of the RSS feed to serve")
+	fs.StringVar(&c.fallbackFeed, "fallback-feed", c.fallbackFeed, "URL of an RSS feed to serve while -feed keeps failing, empty for none")
+	fs.DurationVar(&c.connectTimeout, "connect-timeout", c.connectTimeout, "timeout for connecting to the feed host")
+	fs.DurationVar(&c.readTimeout, "read-timeout", c.readTimeout, "timeout for the feed host to start responding")
+	fs.DurationVar(&c.fetchTimeout, "fetch-timeout", c.fetchTimeout, "overall limit on fetching the feed, body included")
+	fs.Float64Var(&c.minFetchRate, "min-fetch-rate", c.minFetchRate, "give up on a feed whose body arrives slower than this many bytes a second over\n"+
+		"-min-rate-window, so a trickling host can't hold a fetch open; 0 to disable")
+	fs.DurationVar(&c.minRateWindow, "min-rate-window", c.minRateWindow, "how long a feed may stay below -min-fetch-rate before it is dropped")
+	fs.Int64Var(&c.maxFeedSize, "max-feed-size", c.maxFeedSize, "give up on a feed whose body is over this many bytes")
+	fs.StringVar(&c.motd, "motd", c.motd, "message of the day shown to every session, and the fallback for -motd-url")
+	fs.StringVar(&c.motdURL, "motd-url", c.motdURL, "URL of a plain text message of the day, so it can change without a restart")
+	fs.DurationVar(&c.motdRefresh, "motd-refresh", c.motdRefresh, "how often -motd-url is refetched")
+	fs.DurationVar(&c.dwell, "dwell", c.dwell, "move the detail view on to the next article after this long, cycling through\n"+
+		"the feed (space pauses); 0 to disable")
+	fs.BoolVar(&c.pauseOnBlur, "pause-on-blur", c.pauseOnBlur, "ask terminals to report focus changes and pause -dwell while they are in the background")
+	fs.BoolVar(&c.linkPreview, "link-preview", c.linkPreview, "fetch the page behind each opened article and show its Open Graph title, description\n"+
+		"and image (one extra request per article per session)")
+	fs.StringVar(&c.sort, "sort", c.sort, "order items by these keys in turn, later ones breaking ties, e.g. date,author;\n"+
+		"keys are date (newest first), title and author; empty keeps the feed's order")
+	fs.Var(&c.linkSchemes, "link-schemes", "comma-separated URL schemes feed links may use; others are dropped rather than\n"+
+		"shown, copied or opened (javascript, vbscript, data and file are never allowed)")
+	fs.IntVar(&c.maxDescSize, "max-desc-size", c.maxDescSize, "show item descriptions longer than this many bytes as plain text, cut to this length")
+	fs.IntVar(&c.maxDescDepth, "max-desc-depth", c.maxDescDepth, "show item descriptions whose HTML nests deeper than this as plain text")
+	fs.DurationVar(&c.futureSlack, "future-date-slack", c.futureSlack, "flag items dated further than this ahead of the server's clock")
+	fs.DurationVar(&c.maxItemAge, "max-item-age", c.maxItemAge, "flag items dated longer ago than this; 0 to disable")
+	fs.StringVar(&c.splashFile, "splash", c.splashFile, "text file of ASCII art and a tagline to show while the feed loads, in place of the\n"+
+		"built-in one, or none for a plain loading message")
+	fs.DurationVar(&c.splashTime, "splash-time", c.splashTime, "keep the splash up at least this long, even when the feed is already cached (any\n"+
+		"key skips it); 0 to only show it while loading")
+	fs.BoolVar(&c.hideTitleDescriptions, "hide-title-descriptions", c.hideTitleDescriptions, "leave out list descriptions that just repeat the item's title")
+	fs.BoolVar(&c.netrc, "netrc", c.netrc, "authenticate to feed hosts with their login and password from $NETRC, or ~/.netrc")
+	fs.StringVar(&c.stateDir, "state-dir", c.stateDir, "directory for per-user preferences and history, empty (the default) to keep\n"+
+		"nothing on disk")
+	fs.StringVar(&c.exportDir, "export-dir", c.exportDir, "directory for read history exports, empty to copy them to the client's clipboard")
+	fs.StringVar(&c.publicHost, "public-host", c.publicHost, "host, or host:port, that users connect to, for the ssh commands copied with y")
+	fs.StringVar(&c.memberKeys, "member-keys", c.memberKeys, "authorized_keys file of members, whose settings and history are saved; other keys\n"+
+		"get the read-only guest mode (default: anyone with a key is a member)")
+	fs.StringVar(&c.clipDir, "clip-dir", c.clipDir, "directory to save articles to with s, as date-title.md files")
+	fs.StringVar(&c.clipKeys, "clip-keys", c.clipKeys, "authorized_keys file of the users allowed to use -clip-dir; nobody else can")
+	fs.BoolVar(&c.agentNames, "agent-names", c.agentNames, "greet users who connect with ssh -A by their key comment, asking their agent for it (never used for identification)")
+	fs.StringVar(&c.hostKeyDir, "host-key-dir", c.hostKeyDir, "directory of host private keys to offer side by side, for rotating keys without\n"+
+		"breaking known_hosts (default: generate and use .ssh/id_ed25519)")
+	fs.StringVar(&c.primaryHostKey, "primary-host-key", c.primaryHostKey, "file name in -host-key-dir to prefer when two keys share a type")
+	fs.StringVar(&c.auditLog, "audit-log", c.auditLog, "append connections, key fingerprints, client addresses and every article opened to\n"+
+		"this JSON lines file; it ties reading habits to keys, so only enable it if you need it")
+	fs.Int64Var(&c.auditMaxSize, "audit-log-max-size", c.auditMaxSize, "size in MB at which the audit log is moved aside and a new one started")
+	fs.StringVar(&c.openHookURL, "open-hook-url", c.openHookURL, "POST each opened article's title, link, author and reader's key hash to this URL as JSON")
+	fs.StringVar(&c.openHookExec, "open-hook-exec", c.openHookExec, "run this program on the server for each opened article, with its link as the argument\n"+
+		"and details in NEWS_* variables; feed text reaches it unchecked, see the README")
+	fs.DurationVar(&c.openHookTimeout, "open-hook-timeout", c.openHookTimeout, "how long an open hook may run before it is stopped")
+	fs.StringVar(&c.versionURL, "version-url", c.versionURL, "URL serving the latest release's version, to log a notice when this build is\n"+
+		"out of date (nothing is updated automatically); empty to never check")
+	fs.DurationVar(&c.versionCheck, "version-check", c.versionCheck, "how often -version-url is checked")
+	fs.BoolVar(&c.debug, "debug", c.debug, "enable debugging views (x shows an item's raw XML)")
+	fs.StringVar(&c.fetchMode, "fetch", c.fetchMode, "when to first fetch the shared feed: lazy waits for the first session (no upstream\n"+
+		"traffic while nobody is connected, but that session waits for the fetch); eager fetches\n"+
+		"at startup so every session starts instantly")
+	fs.DurationVar(&c.cacheTTL, "cache-ttl", c.cacheTTL, "how often the shared feed is refreshed in the background; when not given,\n"+
+		"the feed's own <ttl> is used if it has one")
+	fs.Float64Var(&c.fetchRate, "fetch-rate", c.fetchRate, "upstream feed requests allowed per minute, 0 for no limit; sessions queue for\n"+
+		"their turn and background refreshes are skipped")
+	fs.IntVar(&c.fetchBurst, "fetch-burst", c.fetchBurst, "upstream feed requests allowed back to back before -fetch-rate applies")
+	fs.Var(c.sanitize, "sanitize", "HTML sanitization policy for a feed as URL=strict|links|ugc (repeatable, default links)")
+	fs.Var(c.include, "include", "only keep a feed's items matching a regular expression, as URL=REGEXP (repeatable;\n"+
+		"the URL ends at the first '=' outside its query string)")
+	fs.Var(c.exclude, "exclude", "drop a feed's items matching a regular expression, as URL=REGEXP (repeatable)")
+	fs.StringVar(&c.filterOn, "filter-on", c.filterOn, "what -include and -exclude match against: title, or all for the description too")
+	fs.Var(c.pins, "pin", "require a feed's host to present a certificate with one of these public keys, as\n"+
+		"URL=sha256/BASE64[,sha256/BASE64...] (repeatable)")
+}
+
+// validate reports settings that parsed but make no sense together, and
+// normalizes the ones that are merely sloppy.
+func (c *config) validate() error {
+	if c.fetchMode != fetchLazy && c.fetchMode != fetchEager {
+		return fmt.Errorf("-fetch must be %s or %s, got %q", fetchLazy, fetchEager, c.fetchMode)
+	}
+	if c.cacheTTL <= 0 {
+		return fmt.Errorf("-cache-ttl must be positive, got %s", c.cacheTTL)
+	}
+	if c.fetchTimeout <= 0 {
+		return fmt.Errorf("-fetch-timeout must be positive, got %s", c.fetchTimeout)
+	}
+	if c.minFetchRate < 0 {
+		return fmt.Errorf("-min-fetch-rate must not be negative, got %g", c.minFetchRate)
+	}
+	if c.minRateWindow <= 0 {
+		return fmt.Errorf("-min-rate-window must be positive, got %s", c.minRateWindow)
+	}
+	if c.maxFeedSize <= 0 {
+		return fmt.Errorf("-max-feed-size must be positive, got %d", c.maxFeedSize)
+	}
+	if c.fetchRate < 0 {
+		return fmt.Errorf("-fetch-rate must not be negative, got %g", c.fetchRate)
+	}
+	if c.fetchBurst < 1 {
+		return fmt.Errorf("-fetch-burst must be at least 1, got %d", c.fetchBurst)
+	}
+	if c.dwell < 0 {
+		return fmt.Errorf("-dwell must not be negative, got %s", c.dwell)
+	}
+	if c.maxDescSize <= 0 {
+		return fmt.Errorf("-max-desc-size must be positive, got %d", c.maxDescSize)
+	}
+	if c.maxDescDepth <= 0 {
+		return fmt.Errorf("-max-desc-depth must be positive, got %d", c.maxDescDepth)
+	}
+	if c.futureSlack < 0 {
+		return fmt.Errorf("-future-date-slack must not be negative, got %s", c.futureSlack)
+	}
+	if c.maxItemAge < 0 {
+		return fmt.Errorf("-max-item-age must not be negative, got %s", c.maxItemAge)
+	}
+	if c.splashTime < 0 {
+		return fmt.Errorf("-splash-time must not be negative, got %s", c.splashTime)
+	}
+	if c.auditMaxSize <= 0 {
+		return fmt.Errorf("-audit-log-max-size must be positive, got %d", c.auditMaxSize)
+	}
+	if c.openHookURL != "" && c.openHookExec != "" {
+		return fmt.Errorf("-open-hook-url and -open-hook-exec can't both be set")
+	}
+	if c.openHookTimeout <= 0 {
+		return fmt.Errorf("-open-hook-timeout must be positive, got %s", c.openHookTimeout)
+	}
+	if c.primaryHostKey != "" && c.hostKeyDir == "" {
+		return fmt.Errorf("-primary-host-key needs -host-key-dir")
+	}
+	feedURL, err := normalizeFeedURL(c.feedURL)
+	if err != nil {
+		return err
+	}
+	c.feedURL = feedURL
+	if c.fallbackFeed != "" {
+		if c.fallbackFeed, err = normalizeFeedURL(c.fallbackFeed); err != nil {
+			return fmt.Errorf("-fallback-feed: %w", err)
+		}
+	}
+	// Per-feed settings are looked up by the normalized URL, so a key
+	// written differently from -feed would otherwise be silently ignored,
+	// which for -pin means fetching unpinned.
+	for _, f := range []struct {
+		name string
+		p    *perFeed
+	}{{"-sanitize", &c.sanitize}, {"-include", &c.include}, {"-exclude", &c.exclude}, {"-pin", &c.pins}} {
+		if *f.p, err = f.p.normalized(); err != nil {
+			return fmt.Errorf("%s: %w", f.name, err)
+		}
+	}
+	for url := range c.pins {
+		if url != c.feedURL && url != c.fallbackFeed {
+			return fmt.Errorf("-pin for %s: not the -feed or -fallback-feed", url)
+		}
+	}
+	if c.motdURL != "" {
+		if c.motdURL, err = normalizeFeedURL(c.motdURL); err != nil {
+			return fmt.Errorf("-motd-url: %w", err)
+		}
+		if c.motdRefresh <= 0 {
+			return fmt.Errorf("-motd-refresh must be positive, got %s", c.motdRefresh)
+		}
+	}
+	if c.versionURL != "" {
+		if c.versionURL, err = normalizeFeedURL(c.versionURL); err != nil {
+			return fmt.Errorf("-version-url: %w", err)
+		}
+		if c.versionCheck <= 0 {
+			return fmt.Errorf("-version-check must be positive, got %s", c.versionCheck)
+		}
+	}
+	if (c.clipDir == "") != (c.clipKeys == "") {
+		return fmt.Errorf("-clip-dir and -clip-keys go together, since saving writes to this server's disk")
+	}
+	if c.clipKeys != "" {
+		if c.clipUsers, err = loadAuthorizedUsers(c.clipKeys); err != nil {
+			return fmt.Errorf("-clip-keys: %w", err)
+		}
+	}
+	if c.openHookURL != "" {
+		if u, err := url.Parse(c.openHookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("-open-hook-url must be an http or https URL, got %q", c.openHookURL)
+		}
+	}
+	if c.openHookExec != "" {
+		if c.openHookExec, err = exec.LookPath(c.openHookExec); err != nil {
+			return fmt.Errorf("-open-hook-exec: %w", err)
+		}
+	}
+	switch c.splashFile {
+	case "":
+	case "none":
+		c.splash = ""
+	default:
+		if c.splash, err = loadSplash(c.splashFile); err != nil {
+			return fmt.Errorf("-splash: %w", err)
+		}
+	}
+	if c.memberKeys != "" {
+		if c.memberUsers, err = loadAuthorizedUsers(c.memberKeys); err != nil {
+			return fmt.Errorf("-member-keys: %w", err)
+		}
+	}
+	if c.sortKeys, err = parseSortKeys(c.sort); err != nil {
+		return fmt.Errorf("-sort: %w", err)
+	}
+	if c.filterOn != filterOnTitle && c.filterOn != filterOnAll {
+		return fmt.Errorf("-filter-on must be %s or %s, got %q", filterOnTitle, filterOnAll, c.filterOn)
+	}
+	if c.filters, err = compileFilters(c.include, c.exclude); err != nil {
+		return err
+	}
+	for url, pins := range c.pins {
+		if _, err := parsePins(pins); err != nil {
+			return fmt.Errorf("-pin for %s: %w", url, err)
+		}
+	}
+	for url, name := range c.sanitize {
+		if _, ok := sanitizePolicies[name]; !ok {
+			return fmt.Errorf("unknown sanitize policy %q for %s", name, url)
+		}
+	}
+	return nil
+}
+
+const (
+	fetchLazy  = "lazy"
+	fetchEager = "eager"
+)
+
+// perFeed is a repeatable flag of feed-specific settings, each given as
+// URL=value; see splitPerFeed for where the URL ends.
+type perFeed map[string]string
+
+func (p perFeed) String() string {
+	var pairs []string
+	for url, v := range p {
+		pairs = append(pairs, url+"="+v)
+	}
+	slices.Sort(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (p perFeed) Set(s string) error {
+	i := splitPerFeed(s)
+	if i <= 0 || i == len(s)-1 {
+		return fmt.Errorf("expected URL=value, got %q", s)
+	}
+	p[s[:i]] = s[i+1:]
+	return nil
+}
+
+// normalized is p with its URLs normalized as normalizeFeedURL does.
+func (p perFeed) normalized() (perFeed, error) {
+	out := make(perFeed, len(p))
+	for raw, v := range p {
+		url, err := normalizeFeedURL(raw)
+		if err != nil {
+			return nil, err
+		}
+		out[url] = v
+	}
+	return out, nil
+}
+
+// splitPerFeed returns the index of the '=' ending the URL in s: the first
+// one that isn't a query parameter's, so values may hold '=' themselves (a
+// pin's base64 padding, say) and URLs may keep their query strings. A
+// parameter without a value, as in ?raw=strict, leaves none to find, so
+// then the last '=' is taken; -1 if there is none at all.
+func splitPerFeed(s string) int {
+	start := strings.Index(s, "://")
+	if start < 0 {
+		start = 0
+	}
+	inParam := false
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '?', '&':
+			inParam = true
+		case '=':
+			if !inParam {
+				return i
+			}
+			inParam = false
+		}
+	}
+	return strings.LastIndex(s, "=")
+}
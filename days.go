@@ -0,0 +1,104 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// dayHeader is a row naming the day of the items below it. The cursor
+// skips over it (see skipHeader), and its empty FilterValue keeps it out
+// of filtered results.
+type dayHeader struct{ label string }
+
+func (h dayHeader) FilterValue() string { return "" }
+
+// dayLabel names the day of t relative to now.
+func dayLabel(t, now time.Time) string {
+	t = t.In(now.Location())
+	y, mo, d := now.Date()
+	today := time.Date(y, mo, d, 0, 0, 0, 0, now.Location())
+	switch day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, now.Location()); {
+	case day.Equal(today):
+		return "Today"
+	case day.Equal(today.AddDate(0, 0, -1)):
+		return "Yesterday"
+	case t.Year() != now.Year():
+		return t.Format("Jan 2, 2006")
+	}
+	return t.Format("Jan 2")
+}
+
+// groupByDay gathers items under a header for each day they were
+// published on, days in the order they first appear and items keeping
+// their order within a day. Undated items go last, without a header.
+func groupByDay(items []list.Item, now time.Time) []list.Item {
+	var days []string
+	byDay := map[string][]list.Item{}
+	var undated []list.Item
+	for _, it := range items {
+		item, ok := it.(rssListItem)
+		if !ok || item.published.IsZero() {
+			undated = append(undated, it)
+			continue
+		}
+		label := dayLabel(item.published, now)
+		if _, ok := byDay[label]; !ok {
+			days = append(days, label)
+		}
+		byDay[label] = append(byDay[label], it)
+	}
+	out := make([]list.Item, 0, len(items)+len(days))
+	for _, label := range days {
+		out = append(out, dayHeader{label})
+		out = append(out, byDay[label]...)
+	}
+	return append(out, undated...)
+}
+
+// toggleDays turns the day headers on or off, keeping the cursor on the
+// same item.
+func (m *model) toggleDays() tea.Cmd {
+	m.state.GroupByDay = !m.state.GroupByDay
+	selected, _ := m.list.SelectedItem().(rssListItem)
+	m.setItems()
+	m.reselect(selected)
+	return m.saveState()
+}
+
+// skipHeader moves the cursor off a header, onward in the direction it
+// came from (from being the index it was at before) or back if there is
+// nothing that way.
+func (m *model) skipHeader(from int) {
+	items := m.list.VisibleItems()
+	i := m.list.Index()
+	if i >= len(items) {
+		return
+	}
+	if _, ok := items[i].(dayHeader); !ok {
+		return
+	}
+	dir := 1
+	if i < from {
+		dir = -1
+	}
+	for _, d := range []int{dir, -dir} {
+		for j := i + d; j >= 0 && j < len(items); j += d {
+			if _, ok := items[j].(dayHeader); !ok {
+				m.list.Select(j)
+				return
+			}
+		}
+	}
+}
+
+var dayHeaderStyle = lipgloss.NewStyle().Bold(true).Underline(true).PaddingLeft(2)
+
+// renderHeader fills the rows of an item with h's label on the first.
+func (d itemDelegate) renderHeader(w io.Writer, h dayHeader) {
+	io.WriteString(w, dayHeaderStyle.Render(h.label)+strings.Repeat("\n", d.Height()-1))
+}
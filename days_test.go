@@ -0,0 +1,94 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDayLabel(t *testing.T) {
+	now := time.Date(2025, 6, 3, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{time.Date(2025, 6, 3, 0, 5, 0, 0, time.UTC), "Today"},
+		{time.Date(2025, 6, 2, 23, 59, 0, 0, time.UTC), "Yesterday"},
+		// Late on the 2nd in New York is already the 3rd in UTC.
+		{time.Date(2025, 6, 2, 22, 0, 0, 0, time.FixedZone("EDT", -4*3600)), "Today"},
+		{time.Date(2025, 1, 2, 12, 0, 0, 0, time.UTC), "Jan 2"},
+		{time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC), "Dec 31, 2024"},
+	}
+	for _, tt := range tests {
+		if got := dayLabel(tt.t, now); got != tt.want {
+			t.Errorf("dayLabel(%s) = %q, want %q", tt.t, got, tt.want)
+		}
+	}
+}
+
+func dayFeed() RSSFeed {
+	feed := testFeed()
+	feed.Items = []RSSItem{
+		{Title: "Morning vote", Link: "https://example.com/1", PublishDate: "Tue, 03 Jun 2025 09:00:00 +0000"},
+		{Title: "Undated note", Link: "https://example.com/2"},
+		{Title: "Late session", Link: "https://example.com/3", PublishDate: "Mon, 02 Jun 2025 22:00:00 +0000"},
+		{Title: "Early briefing", Link: "https://example.com/4", PublishDate: "Tue, 03 Jun 2025 07:00:00 +0000"},
+	}
+	return feed
+}
+
+func TestGroupByDay(t *testing.T) {
+	now := time.Date(2025, 6, 3, 12, 0, 0, 0, time.UTC)
+	got := groupByDay(toListItems(dayFeed().Items), now)
+	var rows []string
+	for _, it := range got {
+		switch it := it.(type) {
+		case dayHeader:
+			rows = append(rows, "# "+it.label)
+		case rssListItem:
+			rows = append(rows, it.title)
+		}
+	}
+	want := "# Today|Morning vote|Early briefing|# Yesterday|Late session|Undated note"
+	if strings.Join(rows, "|") != want {
+		t.Errorf("rows = %q, want %q", strings.Join(rows, "|"), want)
+	}
+
+	m := newModel(dayFeed(), userState{GroupByDay: true}, 80, 40)
+	if info := m.infoMarkdown(); len(m.list.Items()) == len(dayFeed().Items) || !strings.Contains(info, "**Items:** 4\n") {
+		t.Errorf("with %d rows listed, info = %q, want 4 items", len(m.list.Items()), info)
+	}
+}
+
+func TestCursorSkipsDayHeaders(t *testing.T) {
+	var m tea.Model = newModel(dayFeed(), userState{}, 80, 40)
+	m, _ = m.Update(keyMsg("t"))
+	selected := func() string {
+		item, _ := m.(model).list.SelectedItem().(rssListItem)
+		return item.title
+	}
+	if !m.(model).state.GroupByDay || selected() != "Morning vote" {
+		t.Fatalf("after grouping, selected = %q", selected())
+	}
+	m, _ = m.Update(keyMsg("up"))
+	if selected() != "Morning vote" {
+		t.Errorf("up from the first item selected %q", selected())
+	}
+	m, _ = m.Update(keyMsg("down"))
+	m, _ = m.Update(keyMsg("down"))
+	if selected() != "Late session" {
+		t.Errorf("down past a header selected %q", selected())
+	}
+	m, _ = m.Update(keyMsg("up"))
+	if selected() != "Early briefing" {
+		t.Errorf("up past a header selected %q", selected())
+	}
+
+	m, _ = m.Update(keyMsg("t"))
+	if _, ok := m.(model).list.Items()[0].(list.DefaultItem); !ok || selected() != "Early briefing" {
+		t.Errorf("after ungrouping, selected = %q", selected())
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// showRawXML shows the current item's XML in place of the detail view.
+func (m *model) showRawXML() tea.Cmd {
+	item, ok := m.current()
+	if !ok {
+		return m.noSelection()
+	}
+	m.showRaw = true
+	m.rawItem = item.rss
+	return nil
+}
+
+// rawItemMarkdown shows an item the way the feed sent it next to what we
+// parsed out of it, for diagnosing parsing quirks. Only reachable with -debug.
+func rawItemMarkdown(item RSSItem) string {
+	var b strings.Builder
+	b.WriteString("# Raw item\n\n")
+	fields := []struct{ name, value string }{
+		{"Title", item.Title},
+		{"Link", item.Link},
+		{"GUID", item.Id},
+		{"isPermaLink", item.IsPermaLink},
+		{"pubDate", item.PublishDate},
+		{"Creator", item.Creator},
+		{"Comments", item.commentsLink()},
+	}
+	for _, f := range fields {
+		fmt.Fprintf(&b, "- **%s:** %s\n", f.name, codeSpan(f.value))
+	}
+	if n, ok := item.commentCount(); ok {
+		fmt.Fprintf(&b, "- **Comment count:** `%d`\n", n)
+	}
+	b.WriteString("\n" + codeBlock("<item>"+item.Raw+"</item>", "xml"))
+	return b.String()
+}
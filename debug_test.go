@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestRawItemMarkdownFences(t *testing.T) {
+	item := RSSItem{
+		Title: "The `budget` vote",
+		Id:    "``budget-1``",
+		Raw:   "<title>Fences</title><description>```\n# not a heading\n````</description>",
+	}
+	src := []byte(rawItemMarkdown(item))
+	var code, spans []string
+	doc := goldmark.New().Parser().Parse(text.NewReader(src))
+	gast.Walk(doc, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *gast.FencedCodeBlock:
+			var b strings.Builder
+			for i := 0; i < n.Lines().Len(); i++ {
+				line := n.Lines().At(i)
+				b.Write(line.Value(src))
+			}
+			code = append(code, b.String())
+		case *gast.CodeSpan:
+			spans = append(spans, strings.TrimSpace(string(n.Text(src))))
+		case *gast.Heading:
+			if string(n.Text(src)) != "Raw item" {
+				t.Errorf("raw XML escaped its fence into a heading:\n%s", src)
+			}
+		}
+		return gast.WalkContinue, nil
+	})
+	want := "<item>" + item.Raw + "</item>\n"
+	if len(code) != 1 || code[0] != want {
+		t.Errorf("code blocks = %q, want just %q", code, want)
+	}
+	for _, s := range []string{item.Title, item.Id} {
+		if !strings.Contains(strings.Join(spans, "\n"), s) {
+			t.Errorf("code spans %q are missing %q", spans, s)
+		}
+	}
+}
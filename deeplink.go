@@ -0,0 +1,81 @@
+package main
+
+import (
+	"net"
+	"strings"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+)
+
+// shareCommand is the ssh command that opens item: ssh with the item's key
+// (its guid, or link without one) as the remote command, which
+// teaHandler passes on to openDeepLink.
+func shareCommand(item rssListItem) string {
+	h, p, err := net.SplitHostPort(cfg.publicHost)
+	if err != nil {
+		h, p = cfg.publicHost, ""
+	}
+	// -t since ssh skips the terminal for remote commands, and the
+	// reader needs one.
+	cmd := "ssh -t "
+	if p != "" && p != "22" {
+		cmd += "-p " + p + " "
+	}
+	return cmd + h + " -- " + shellQuote(item.key())
+}
+
+// shellQuote single-quotes s unless it is made only of characters no
+// shell treats specially.
+func shellQuote(s string) string {
+	safe := s != ""
+	for _, r := range s {
+		if !strings.ContainsRune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./:@%+=,", r) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// copyShareCommand copies the selected item's shareCommand to the
+// client's clipboard over OSC 52.
+func (m *model) copyShareCommand() tea.Cmd {
+	item, ok := m.current()
+	if !ok || item.key() == "" {
+		return m.noSelection()
+	}
+	cmd := shareCommand(item)
+	if m.out != nil {
+		if _, err := osc52.New(cmd).WriteTo(m.out); err != nil {
+			log.Error("Failed to copy share command", "error", err)
+			return m.list.NewStatusMessage("Couldn't copy the link")
+		}
+	}
+	return m.list.NewStatusMessage("Copied: " + cmd)
+}
+
+// deepLinkMsg asks for the deepLink to be opened from a warm cache; with a
+// cold one it is opened when the feedMsg arrives.
+type deepLinkMsg struct{}
+
+// openDeepLink opens the item the session was started for, once the feed
+// is in.
+func (m *model) openDeepLink() tea.Cmd {
+	k := m.deepLink
+	if k == "" {
+		return nil
+	}
+	m.deepLink = ""
+	for i, it := range m.list.Items() {
+		if item, ok := it.(rssListItem); ok && item.key() == k {
+			m.list.Select(i)
+			return m.openArticle(item)
+		}
+	}
+	return m.list.NewStatusMessage("That article is no longer in the feed")
+}
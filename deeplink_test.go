@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestShareCommand(t *testing.T) {
+	tests := []struct {
+		host, key, want string
+	}{
+		{"politics.news", "budget-1", "ssh -t politics.news -- budget-1"},
+		{"politics.news:22", "budget-1", "ssh -t politics.news -- budget-1"},
+		{"news.example.com:2222", "https://example.com/a?id=1&x=2", "ssh -t -p 2222 news.example.com -- 'https://example.com/a?id=1&x=2'"},
+		{"politics.news", "it's", `ssh -t politics.news -- 'it'\''s'`},
+	}
+	for _, tt := range tests {
+		c := cfg
+		c.publicHost = tt.host
+		withConfig(t, c)
+		if got := shareCommand(rssListItem{rss: RSSItem{Id: tt.key}}); got != tt.want {
+			t.Errorf("%s, %s: got %q, want %q", tt.host, tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestCopyShareCommand(t *testing.T) {
+	var out bytes.Buffer
+	m := newModel(testFeed(), userState{}, 80, 24)
+	m.out = &out
+	var tm tea.Model = m
+	tm, _ = tm.Update(keyMsg("y"))
+	want := "ssh -t politics.news -- budget-1"
+	if !strings.Contains(out.String(), base64.StdEncoding.EncodeToString([]byte(want))) {
+		t.Errorf("clipboard escape %q doesn't carry %q", out.String(), want)
+	}
+	if v := tm.View(); !strings.Contains(v, "Copied: "+want) {
+		t.Errorf("no confirmation:\n%s", v)
+	}
+}
+
+func TestOpenDeepLink(t *testing.T) {
+	// Warm cache: Init asks for it to be opened.
+	m := newModel(testFeed(), userState{}, 80, 24)
+	m.deepLink = "bill-1"
+	var tm tea.Model = m
+	tm, _ = tm.Update(m.Init()())
+	if got := tm.(model); !got.showDetail || got.selected.title != "Governor signs bill" {
+		t.Errorf("warm: showDetail = %v, selected %q", got.showDetail, got.selected.title)
+	}
+
+	// Cold cache: it opens once the feed is in.
+	m = newModel(RSSFeed{}, userState{}, 80, 24)
+	m.loading = true
+	m.deepLink = "bill-1"
+	tm, _ = m.Update(feedMsg{feed: testFeed()})
+	if got := tm.(model); !got.showDetail || got.selected.title != "Governor signs bill" || got.deepLink != "" {
+		t.Errorf("cold: showDetail = %v, selected %q", got.showDetail, got.selected.title)
+	}
+
+	m = newModel(testFeed(), userState{}, 80, 24)
+	m.deepLink = "gone-1"
+	tm, _ = m.Update(deepLinkMsg{})
+	if v := tm.View(); tm.(model).showDetail || !strings.Contains(v, "no longer in the feed") {
+		t.Errorf("missing item: showDetail = %v\n%s", tm.(model).showDetail, v)
+	}
+}
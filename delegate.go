@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// itemDelegate renders feed items with the user's display preferences on top
+// of the default delegate.
+type itemDelegate struct {
+	list.DefaultDelegate
+	showAuthor bool
+	tintByAge  bool
+	// descLines is how many lines of description each item gets.
+	descLines int
+}
+
+// maxDescLines bounds how tall + can make an item.
+const maxDescLines = 5
+
+// noDescLines is stored in userState.DescLines to hide descriptions, since
+// its zero value means the default of one line.
+const noDescLines = -1
+
+// descLines is how many description lines the user wants per item.
+func (m *model) descLines() int {
+	switch n := m.state.DescLines; {
+	case n == noDescLines:
+		return 0
+	case n == 0:
+		return 1
+	default:
+		return min(n, maxDescLines)
+	}
+}
+
+// changeDescLines shows delta more (or fewer) description lines per item.
+func (m *model) changeDescLines(delta int) tea.Cmd {
+	n := min(max(m.descLines()+delta, 0), maxDescLines)
+	switch n {
+	case 0:
+		m.state.DescLines = noDescLines
+	case 1:
+		m.state.DescLines = 0
+	default:
+		m.state.DescLines = n
+	}
+	m.applyDelegate()
+	msg := fmt.Sprintf("Showing %d description lines", n)
+	if n == 1 {
+		msg = "Showing 1 description line"
+	}
+	return tea.Batch(m.saveState(), m.list.NewStatusMessage(msg))
+}
+
+// newItemDelegate builds the delegate for m's settings. Monochrome sessions
+// (NO_COLOR or a terminal without colors) never get the age tint, and peek
+// mode packs the list down to one headline per line whatever descLines is.
+func newItemDelegate(m *model) itemDelegate {
+	d := itemDelegate{
+		DefaultDelegate: list.NewDefaultDelegate(),
+		showAuthor:      !m.state.HideAuthor,
+		tintByAge:       !m.state.NoAgeTint && !m.monochrome,
+	}
+	if !m.peek {
+		d.descLines = m.descLines()
+	}
+	d.ShowDescription = d.descLines > 0
+	if d.ShowDescription {
+		d.SetHeight(1 + d.descLines)
+	}
+	if m.peek {
+		d.SetSpacing(0)
+	}
+	return d
+}
+
+func (d itemDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if h, ok := item.(dayHeader); ok {
+		d.renderHeader(w, h)
+		return
+	}
+	i, ok := item.(rssListItem)
+	if !ok {
+		d.DefaultDelegate.Render(w, m, index, item)
+		return
+	}
+	if d.tintByAge && !i.published.IsZero() {
+		d.Styles.NormalTitle = d.Styles.NormalTitle.Foreground(ageColor(time.Since(i.published)))
+	}
+	// The default delegate shows one description line per "\n", so wrap
+	// it ourselves to fill the lines we have.
+	if d.descLines > 1 {
+		width := m.Width() - d.Styles.NormalDesc.GetHorizontalFrameSize()
+		i.desc = ansi.Wrap(strings.Join(strings.Fields(i.desc), " "), width, "")
+		item = i
+	}
+	if d.showAuthor && i.author != "" {
+		item = bylineItem{i}
+	}
+	// Filtered out of order, "(1/2)" no longer says which is which.
+	if m.FilterState() != list.Unfiltered && i.dupCount > 1 {
+		if s := i.disambiguator(d.showAuthor); s != "" {
+			item = suffixedItem{item.(list.DefaultItem), " · " + s}
+		}
+	}
+	d.DefaultDelegate.Render(w, m, index, item)
+}
+
+// disambiguator tells r apart from other items with its title: its date,
+// and its author unless the byline already shows it.
+func (r rssListItem) disambiguator(authorShown bool) string {
+	var parts []string
+	if !r.published.IsZero() {
+		parts = append(parts, r.published.Format("Jan 2 15:04"))
+	}
+	if !authorShown && r.author != "" {
+		parts = append(parts, r.author)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// suffixedItem appends to another item's title, after it for the same
+// reason as bylineItem.
+type suffixedItem struct {
+	list.DefaultItem
+	suffix string
+}
+
+func (s suffixedItem) Title() string { return s.DefaultItem.Title() + s.suffix }
+
+// bylineItem appends the author to the title. It goes after the title so
+// filter match positions, which index into the title, stay valid.
+type bylineItem struct{ rssListItem }
+
+func (b bylineItem) Title() string { return b.rssListItem.Title() + " · " + b.author }
+
+var (
+	freshColor, _ = colorful.Hex("#FFD75F")
+	staleColor, _ = colorful.Hex("#6C6C6C")
+)
+
+// ageFadeOver is how long a title takes to fade from fresh to stale.
+const ageFadeOver = 48 * time.Hour
+
+// ageColor blends from freshColor for brand new items to staleColor for items
+// ageFadeOver old or more.
+func ageColor(age time.Duration) lipgloss.Color {
+	t := float64(age) / float64(ageFadeOver)
+	t = min(max(t, 0), 1)
+	return lipgloss.Color(freshColor.BlendLab(staleColor, t).Clamped().Hex())
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestDelegateDescLines(t *testing.T) {
+	m := newModel(testFeed(), userState{DescLines: 3}, 60, 40)
+	d := newItemDelegate(&m)
+	desc := strings.Repeat("The vote was close and the debate ran long into the night. ", 4)
+	for _, author := range []string{"", "Jane Reporter"} {
+		var buf bytes.Buffer
+		d.Render(&buf, m.list, 0, rssListItem{title: "Senate passes budget", desc: desc, author: author})
+		if lines := strings.Count(buf.String(), "\n") + 1; lines != 4 {
+			t.Errorf("author %q: rendered %d lines, want a title and 3 of description:\n%s", author, lines, buf.String())
+		}
+	}
+}
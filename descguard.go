@@ -0,0 +1,92 @@
+package main
+
+import (
+	"html"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	xhtml "golang.org/x/net/html"
+)
+
+// voidElements never have an end tag, so they don't nest.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// htmlDepth is how deeply s nests its elements. Unclosed tags count as
+// still open, so malformed markup comes out deep too.
+func htmlDepth(s string) int {
+	depth, deepest := 0, 0
+	z := xhtml.NewTokenizer(strings.NewReader(s))
+	for {
+		switch z.Next() {
+		case xhtml.ErrorToken:
+			return deepest
+		case xhtml.StartTagToken:
+			name, _ := z.TagName()
+			if !voidElements[string(name)] {
+				depth++
+				deepest = max(deepest, depth)
+			}
+		case xhtml.EndTagToken:
+			depth = max(depth-1, 0)
+		}
+	}
+}
+
+// stripTags is the text of s without any markup, skipping script and
+// style contents. It is a single pass of the tokenizer, so it stays quick
+// on markup too heavy for the sanitizer.
+func stripTags(s string) string {
+	var b strings.Builder
+	skip := 0
+	z := xhtml.NewTokenizer(strings.NewReader(s))
+	for {
+		switch z.Next() {
+		case xhtml.ErrorToken:
+			return strings.Join(strings.Fields(b.String()), " ")
+		case xhtml.StartTagToken:
+			if name, _ := z.TagName(); string(name) == "script" || string(name) == "style" {
+				skip++
+			}
+		case xhtml.EndTagToken:
+			if name, _ := z.TagName(); (string(name) == "script" || string(name) == "style") && skip > 0 {
+				skip--
+			}
+		case xhtml.TextToken:
+			if skip == 0 {
+				b.Write(z.Text())
+				b.WriteByte(' ')
+			}
+		}
+	}
+}
+
+// flattenHeavyDescriptions replaces descriptions longer than
+// -max-desc-size or nested deeper than -max-desc-depth with their plain
+// text, cut to -max-desc-size and escaped so the sanitizer passes it
+// through as is. It runs once
+// per fetch, so each fallback is logged once per refresh rather than on
+// every redraw.
+func flattenHeavyDescriptions(feedURL string, items []RSSItem) []RSSItem {
+	for i, item := range items {
+		desc := item.Description
+		reason := ""
+		if len(desc) > cfg.maxDescSize {
+			reason = "size"
+		} else if htmlDepth(desc) > cfg.maxDescDepth {
+			reason = "nesting"
+		}
+		if reason == "" {
+			continue
+		}
+		log.Warn("Showing an item description as plain text", "feed", feedURL, "item", item.key(), "reason", reason, "bytes", len(desc))
+		text := stripTags(desc)
+		if len(text) > cfg.maxDescSize {
+			text = strings.ToValidUTF8(text[:cfg.maxDescSize], "") + "…"
+		}
+		items[i].Description = html.EscapeString(text)
+	}
+	return items
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHTMLDepth(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"plain text", 0},
+		{"<p>one <b>two</b></p><p>three</p>", 2},
+		{"<p>line<br>break<img src=x></p>", 1},
+		{strings.Repeat("<div>", 100), 100},
+		{strings.Repeat("<i>", 3) + strings.Repeat("</i>", 10) + "<b>", 3},
+	}
+	for _, tt := range tests {
+		if got := htmlDepth(tt.in); got != tt.want {
+			t.Errorf("htmlDepth(%.40q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFlattenHeavyDescriptions(t *testing.T) {
+	c := cfg
+	c.maxDescDepth = 8
+	c.maxDescSize = 200
+	withConfig(t, c)
+
+	items := []RSSItem{
+		{Id: "fine", Description: "<p>Read <a href='https://example.com'>this</a></p>"},
+		{Id: "deep", Description: strings.Repeat("<div>", 50) + "Deep &amp; <script>alert(1)</script>down"},
+		{Id: "big", Description: "<p>" + strings.Repeat("word ", 100) + "</p>"},
+	}
+	got := flattenHeavyDescriptions("https://example.com/rss", items)
+	if got[0].Description != "<p>Read <a href='https://example.com'>this</a></p>" {
+		t.Errorf("flattened a normal description: %q", got[0].Description)
+	}
+	if got[1].Description != "Deep &amp; down" {
+		t.Errorf("deep = %q", got[1].Description)
+	}
+	if d := got[2].Description; len(d) > 200+len("…") || !strings.HasSuffix(d, "…") || strings.Contains(d, "<") {
+		t.Errorf("big = %q", d)
+	}
+	// What's left still renders as the text it was.
+	if md := sanitizeDescription("", got[1].Description); md != "Deep & down" {
+		t.Errorf("rendered deep = %q", md)
+	}
+}
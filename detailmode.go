@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+)
+
+// detailMode is how the detail view shows an article. Glamour sometimes
+// mangles complex markup, so the plain views are there as an escape hatch.
+type detailMode int
+
+const (
+	detailRendered detailMode = iota
+	detailText
+	detailSource
+)
+
+var detailModeNames = [...]string{
+	detailRendered: "rendered",
+	detailText:     "plain text",
+	detailSource:   "HTML source",
+}
+
+func (d detailMode) next() detailMode { return (d + 1) % detailMode(len(detailModeNames)) }
+
+// cycleDetailMode moves the detail view on to the next way of showing the
+// article.
+func (m *model) cycleDetailMode() tea.Cmd {
+	if !m.showDetail {
+		return m.noArticleOpen()
+	}
+	m.detailMode = m.detailMode.next()
+	return nil
+}
+
+// maxRenders bounds the session's cache of glamour output.
+const maxRenders = 32
+
+// detailWrap is the width of the modal's text column. Glamour pads its
+// lines out to the wrap width, so anything wider gets wrapped a second
+// time by the modal, which breaks up tables and code blocks.
+var detailWrap = modalStyle.GetWidth() - modalStyle.GetHorizontalPadding()
+
+// detailBody renders the selected article in m's detail mode.
+func (m model) detailBody() (string, error) {
+	// Neither plain view goes through glamour, so nothing else stops the
+	// feed's text from driving the terminal.
+	title, link := printable(m.selected.title), printable(m.selected.link)
+	switch m.detailMode {
+	case detailText:
+		return title + "\n\n" + printableLines(plainText(m.selected.desc)) + "\n\n" + link, nil
+	case detailSource:
+		return title + "\n\n" + sourceText(m.selected.desc) + "\n\n" + link, nil
+	}
+	return m.render(m.detailMarkdown())
+}
+
+// render renders md to fit the modal, with the results kept for the
+// session, since the view is redrawn far more often than the article
+// changes.
+func (m model) render(md string) (string, error) {
+	if out, ok := m.renders[md]; ok {
+		return out, nil
+	}
+	r, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle("dark"),
+		glamour.WithWordWrap(detailWrap),
+	)
+	if err != nil {
+		return "", err
+	}
+	out, err := r.Render(md)
+	if err != nil {
+		return "", err
+	}
+	if len(m.renders) >= maxRenders {
+		clear(m.renders)
+	}
+	m.renders[md] = out
+	return out, nil
+}
+
+// detailHint is the footer under the article.
+func (m model) detailHint() string {
+	hint := fmt.Sprintf("*Showing %s. Press 'r' for %s, 'o' to open in browser, 'f' for focus mode, Esc to go back.*",
+		detailModeNames[m.detailMode], detailModeNames[m.detailMode.next()])
+	if s := m.dwellStatus(); s != "" {
+		hint += "\n\n*" + s + "*"
+	}
+	return hint
+}
+
+// sourceText is desc with its tags left in but anything that could drive
+// the terminal taken out.
+func sourceText(desc string) string {
+	return strings.TrimSpace(printableLines(desc))
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlainViewsStripEscapes(t *testing.T) {
+	m := newModel(testFeed(), userState{}, 80, 40)
+	m.selected = rssListItem{
+		title: "Budget\x1b[2J vote",
+		desc:  "<p>Line one &#27;]52;c;aGk=&#7;</p>\n<p>Line two</p>",
+		link:  "https://example.com/budget",
+	}
+	for _, mode := range []detailMode{detailText, detailSource} {
+		m.detailMode = mode
+		out, err := m.detailBody()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.ContainsAny(out, "\x1b\a") {
+			t.Errorf("%s view lets escapes through: %q", detailModeNames[mode], out)
+		}
+		if !strings.Contains(out, "Line two") || !strings.Contains(out, "\n") {
+			t.Errorf("%s view lost the text or its lines: %q", detailModeNames[mode], out)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"html"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// key identifies an item across refreshes the same way dedupItems does.
+func (i RSSItem) key() string {
+	if i.Id != "" {
+		return i.Id
+	}
+	return i.Link
+}
+
+// trackEdits returns, for each item in next whose title or description
+// differs from its version in prev, that earlier version. Edits found by
+// earlier refreshes are kept for as long as the item stays in the feed.
+func trackEdits(prev, next []RSSItem, edits map[string]RSSItem) map[string]RSSItem {
+	before := make(map[string]RSSItem, len(prev))
+	for _, item := range prev {
+		before[item.key()] = item
+	}
+	out := map[string]RSSItem{}
+	for _, item := range next {
+		k := item.key()
+		if k == "" {
+			continue
+		}
+		if old, ok := before[k]; ok && (old.Title != item.Title || old.Description != item.Description) {
+			out[k] = old
+		} else if old, ok := edits[k]; ok {
+			out[k] = old
+		}
+	}
+	return out
+}
+
+// setEdits marks the items that changed since they were first seen.
+func (m *model) setEdits(edits map[string]RSSItem) {
+	m.edits = edits
+	items := m.list.Items()
+	for i, it := range items {
+		if item, ok := it.(rssListItem); ok {
+			_, item.edited = edits[item.key()]
+			items[i] = item
+		}
+	}
+	m.setListItems(items)
+}
+
+// showChanges opens the diff of the selected item against its previous
+// version.
+func (m *model) showChanges() tea.Cmd {
+	item, ok := m.current()
+	if !ok {
+		return m.noSelection()
+	}
+	if _, edited := m.edits[item.key()]; !edited {
+		return m.list.NewStatusMessage("This item hasn't changed since it was first seen")
+	}
+	// Diffed once here rather than on every redraw: it is quadratic in
+	// the length of the text.
+	old := m.edits[item.key()]
+	m.showDiff = true
+	m.diffBody = diffText(printable(old.Title), printable(item.title)) + "\n\n" +
+		diffText(printableLines(plainText(old.Description)), printableLines(plainText(item.desc)))
+	return nil
+}
+
+// diffView shows what changed in the item showChanges was asked about,
+// with removed words struck out in [-...-] and added ones in {+...+}, so
+// it reads without color too.
+func (m model) diffView() string {
+	var b strings.Builder
+	b.WriteString(diffTitleStyle.Render("What changed") + "\n\n")
+	b.WriteString(m.diffBody)
+	b.WriteString("\n\n" + diffHintStyle.Render("Press Esc to go back."))
+	return b.String()
+}
+
+// maxDiffCells bounds the LCS table diffTokens builds, which is the
+// product of the two lengths.
+const maxDiffCells = 1 << 20
+
+// diffText renders the difference between a and b word by word, or line
+// by line when they are too long for that, or doesn't try when even the
+// lines are too many.
+func diffText(a, b string) string {
+	if x, y := strings.Fields(a), strings.Fields(b); len(x)*len(y) <= maxDiffCells {
+		return renderDiff(diffTokens(x, y), " ")
+	}
+	if x, y := strings.Split(a, "\n"), strings.Split(b, "\n"); len(x)*len(y) <= maxDiffCells {
+		return renderDiff(diffTokens(x, y), "\n")
+	}
+	return diffHintStyle.Render("The text changed, but is too long to compare.")
+}
+
+// plainText strips a description down to its words.
+func plainText(desc string) string {
+	return html.UnescapeString(sanitizePolicies["strict"].Sanitize(desc))
+}
+
+type diffOp struct {
+	// kind is ' ' for unchanged, '-' for removed and '+' for added.
+	kind byte
+	word string
+}
+
+// diffTokens is a longest-common-subsequence diff of x and y.
+func diffTokens(x, y []string) []diffOp {
+	// lcs[i][j] is the LCS length of x[i:] and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(x) && j < len(y) {
+		switch {
+		case x[i] == y[j]:
+			ops = append(ops, diffOp{' ', x[i]})
+			i, j = i+1, j+1
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{'-', x[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', y[j]})
+			j++
+		}
+	}
+	for ; i < len(x); i++ {
+		ops = append(ops, diffOp{'-', x[i]})
+	}
+	for ; j < len(y); j++ {
+		ops = append(ops, diffOp{'+', y[j]})
+	}
+	return ops
+}
+
+// renderDiff joins runs of the same kind, and the runs, with sep, so a
+// rewritten phrase reads as one removal and one addition.
+func renderDiff(ops []diffOp, sep string) string {
+	var parts []string
+	for i := 0; i < len(ops); {
+		j := i
+		var words []string
+		for ; j < len(ops) && ops[j].kind == ops[i].kind; j++ {
+			words = append(words, ops[j].word)
+		}
+		run := strings.Join(words, sep)
+		switch ops[i].kind {
+		case '-':
+			run = diffRemovedStyle.Render("[-" + run + "-]")
+		case '+':
+			run = diffAddedStyle.Render("{+" + run + "+}")
+		}
+		parts = append(parts, run)
+		i = j
+	}
+	return strings.Join(parts, sep)
+}
+
+var (
+	diffTitleStyle   = lipgloss.NewStyle().Bold(true)
+	diffHintStyle    = lipgloss.NewStyle().Faint(true).Italic(true)
+	diffRemovedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("203")).Strikethrough(true)
+	diffAddedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("114"))
+)
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRenderDiff(t *testing.T) {
+	tests := []struct{ a, b, want string }{
+		{"Senate passes budget", "Senate passes budget", "Senate passes budget"},
+		{"Senate passes budget", "Senate narrowly passes budget", "Senate {+narrowly+} passes budget"},
+		{"The vote was close.", "The vote was not close at all.", "The vote was [-close.-] {+not close at all.+}"},
+		{"", "New text", "{+New text+}"},
+	}
+	for _, tt := range tests {
+		if got := renderDiff(diffTokens(strings.Fields(tt.a), strings.Fields(tt.b)), " "); got != tt.want {
+			t.Errorf("diff(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestTrackEdits(t *testing.T) {
+	v1 := []RSSItem{{Id: "a", Title: "Senate passes budget"}, {Id: "b", Title: "Governor signs bill"}}
+	v2 := []RSSItem{{Id: "a", Title: "Senate narrowly passes budget"}, {Id: "b", Title: "Governor signs bill"}}
+	edits := trackEdits(v1, v2, nil)
+	if len(edits) != 1 || edits["a"].Title != "Senate passes budget" {
+		t.Fatalf("edits = %+v", edits)
+	}
+	// An unchanged refresh keeps the edit; dropping the item forgets it.
+	if edits = trackEdits(v2, v2, edits); edits["a"].Title != "Senate passes budget" {
+		t.Errorf("edit lost on an unchanged refresh: %+v", edits)
+	}
+	if edits = trackEdits(v2, v2[1:], edits); len(edits) != 0 {
+		t.Errorf("edits for dropped items = %+v", edits)
+	}
+}
+
+func TestShowChanges(t *testing.T) {
+	m := newModel(testFeed(), userState{}, 80, 40)
+	m.list.StatusMessageLifetime = time.Millisecond
+	if cmd := m.showChanges(); cmd == nil || m.showDiff {
+		t.Error("diff shown for an unedited item")
+	}
+
+	old := testFeed().Items[0]
+	old.Title = "Senate debates budget"
+	m.setEdits(map[string]RSSItem{old.key(): old})
+	if item := m.list.Items()[0].(rssListItem); !strings.HasSuffix(item.Title(), "✎") {
+		t.Errorf("edited item title = %q", item.Title())
+	}
+	m.showChanges()
+	if !m.showDiff {
+		t.Fatal("diff not shown")
+	}
+	if v := m.diffView(); !strings.Contains(v, "[-debates-] {+passes+}") {
+		t.Errorf("diff view = %q", v)
+	}
+
+	old.Title = "Senate\x1b[2J passes budget"
+	old.Description = "<p>The vote &#27;]52;c;aGk=&#7;was close.</p>"
+	m.setEdits(map[string]RSSItem{old.key(): old})
+	m.showChanges()
+	if strings.ContainsAny(m.diffBody, "\x1b\a") {
+		t.Errorf("diff lets escapes through: %q", m.diffBody)
+	}
+}
+
+func TestDiffTextLimits(t *testing.T) {
+	words := func(n int, w string) string { return strings.TrimSpace(strings.Repeat(w+" ", n)) }
+	long := words(2000, "vote")
+
+	// Too many words for a word diff: changed lines are shown whole.
+	a := long + "\nThe vote was close."
+	b := long + "\nThe vote was not close."
+	if got := diffText(a, b); !strings.Contains(got, "[-The vote was close.-]\n{+The vote was not close.+}") {
+		t.Errorf("line diff = %q", got[max(0, len(got)-80):])
+	}
+
+	// Too many lines as well: no diff at all.
+	lines := strings.Repeat("vote\n", 2000)
+	if got := diffText(lines+"a", lines+"b"); !strings.Contains(got, "too long to compare") {
+		t.Errorf("oversized diff = %q", got[max(0, len(got)-80):])
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// dwellTickMsg counts down the auto-advance timer. Ticks from a timer that
+// has since been restarted or stopped carry an old seq and are dropped.
+type dwellTickMsg struct{ seq int }
+
+func (m *model) dwellTick() tea.Cmd {
+	seq := m.dwellSeq
+	return tea.Tick(time.Second, func(time.Time) tea.Msg { return dwellTickMsg{seq} })
+}
+
+// startDwell (re)starts the countdown to the next article, if -dwell is set
+// and the session isn't in lite mode.
+func (m *model) startDwell() tea.Cmd {
+	if cfg.dwell <= 0 || m.lite {
+		return nil
+	}
+	m.dwellSeq++
+	m.dwellLeft = cfg.dwell
+	m.dwellPaused = false
+	return m.dwellTick()
+}
+
+// toggleDwell pauses or resumes the countdown where it left off.
+func (m *model) toggleDwell() tea.Cmd {
+	if !m.showDetail {
+		return m.noArticleOpen()
+	}
+	if cfg.dwell <= 0 || m.lite {
+		return nil
+	}
+	m.dwellPaused = !m.dwellPaused
+	m.dwellSeq++
+	if m.dwellPaused {
+		return nil
+	}
+	return m.dwellTick()
+}
+
+// updateDwell handles a tick, opening the next article in the list,
+// wrapping at the end, once the countdown runs out. It is opened just as
+// enter would, so it counts as read and the open hook hears of it.
+func (m *model) updateDwell(msg dwellTickMsg) tea.Cmd {
+	if msg.seq != m.dwellSeq || !m.showDetail || m.dwellPaused || m.blurred {
+		return nil
+	}
+	m.dwellLeft -= time.Second
+	if m.dwellLeft > 0 {
+		return m.dwellTick()
+	}
+	items := m.list.VisibleItems()
+	if len(items) == 0 {
+		return nil
+	}
+	// Step over day headers, giving up after a full lap.
+	i := m.list.Index()
+	for range items {
+		i = (i + 1) % len(items)
+		if item, ok := items[i].(rssListItem); ok {
+			m.list.Select(i)
+			return m.openArticle(item)
+		}
+	}
+	return m.startDwell()
+}
+
+// dwellStatus is the countdown shown under the article, or "" without one.
+func (m model) dwellStatus() string {
+	switch {
+	case cfg.dwell <= 0, m.lite:
+		return ""
+	case m.dwellPaused:
+		return "Paused, space to resume."
+	}
+	return fmt.Sprintf("Next article in %s, space to pause.", m.dwellLeft.Round(time.Second))
+}
+
+// setBlurred stops the countdown while the client's terminal is out of
+// focus (-pause-on-blur), so nobody misses an article and idle clients
+// aren't sent redraws, and picks it up again on return.
+func (m *model) setBlurred(blurred bool) tea.Cmd {
+	m.blurred = blurred
+	m.dwellSeq++
+	if blurred || !m.showDetail || m.dwellPaused || cfg.dwell <= 0 || m.lite {
+		return nil
+	}
+	return m.dwellTick()
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestDwellAdvances(t *testing.T) {
+	c := cfg
+	c.dwell = 2 * time.Second
+	withConfig(t, c)
+
+	var m tea.Model = newModel(testFeed(), userState{}, 80, 40)
+	m, _ = m.Update(keyMsg("enter"))
+	tick := func() {
+		m, _ = m.Update(dwellTickMsg{m.(model).dwellSeq})
+	}
+	selected := func() string { return m.(model).selected.title }
+
+	tick()
+	if selected() != "Senate passes budget" {
+		t.Fatalf("advanced early to %q", selected())
+	}
+	if s := m.(model).dwellStatus(); !strings.Contains(s, "1s") {
+		t.Errorf("countdown = %q", s)
+	}
+	tick()
+	if selected() != "Governor signs bill" {
+		t.Fatalf("selected = %q after the dwell, want the next article", selected())
+	}
+	if h := m.(model).state.History; len(h) != 2 || h[1].GUID != "bill-1" {
+		t.Errorf("history = %+v, want the advanced-to article recorded as read", h)
+	}
+
+	// Paused, ticks change nothing; stale ticks never do.
+	m, _ = m.Update(keyMsg(" "))
+	tick()
+	tick()
+	if selected() != "Governor signs bill" || !m.(model).dwellPaused {
+		t.Errorf("advanced while paused to %q", selected())
+	}
+	m, _ = m.Update(keyMsg(" "))
+	stale := m.(model).dwellSeq - 1
+	m, _ = m.Update(dwellTickMsg{stale})
+	if left := m.(model).dwellLeft; left != c.dwell {
+		t.Errorf("stale or paused tick counted: %s left", left)
+	}
+	tick()
+	tick()
+	if selected() != "Senate passes budget" {
+		t.Errorf("selected = %q, want a wrap to the first article", selected())
+	}
+}
+
+func TestDwellDisabled(t *testing.T) {
+	m := newModel(testFeed(), userState{}, 80, 40)
+	if cmd := m.startDwell(); cmd != nil {
+		t.Error("timer started without -dwell")
+	}
+	if s := m.dwellStatus(); s != "" {
+		t.Errorf("status = %q", s)
+	}
+}
+
+func TestDwellPausesOnBlur(t *testing.T) {
+	c := cfg
+	c.dwell = time.Second
+	withConfig(t, c)
+
+	var m tea.Model = newModel(testFeed(), userState{}, 80, 40)
+	m, _ = m.Update(keyMsg("enter"))
+	seq := m.(model).dwellSeq
+	m, _ = m.Update(tea.BlurMsg{})
+	m, _ = m.Update(dwellTickMsg{m.(model).dwellSeq})
+	if got := m.(model).selected.title; got != "Senate passes budget" {
+		t.Fatalf("advanced to %q while blurred", got)
+	}
+	var cmd tea.Cmd
+	m, cmd = m.Update(tea.FocusMsg{})
+	if cmd == nil || m.(model).dwellSeq == seq {
+		t.Fatal("countdown not resumed on focus")
+	}
+	m, _ = m.Update(dwellTickMsg{m.(model).dwellSeq})
+	if got := m.(model).selected.title; got != "Governor signs bill" {
+		t.Errorf("selected = %q after focus returned, want the next article", got)
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func (m *model) toggleInfo() tea.Cmd {
+	m.showInfo = !m.showInfo
+	return nil
+}
+
+// infoMarkdown describes the feed being served and how it is refreshed.
+func (m model) infoMarkdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", escapeMarkdown(m.feedTitle))
+	fmt.Fprintf(&b, "- **Feed:** %s\n", m.feedURL)
+	if m.homepage != "" {
+		fmt.Fprintf(&b, "- **Homepage:** %s\n", m.homepage)
+	}
+	fmt.Fprintf(&b, "- **Items:** %d\n", len(m.items))
+	if m.feedTTL > 0 {
+		fmt.Fprintf(&b, "- **Publisher TTL:** %s\n", m.feedTTL)
+	} else {
+		b.WriteString("- **Publisher TTL:** not given\n")
+	}
+	refresh := cfg.cacheTTL
+	if feeds != nil {
+		refresh = feeds.interval()
+		fmt.Fprintf(&b, "- **Refreshed every:** %s\n", refresh)
+	}
+	b.WriteString("\n" + m.fetchModeMarkdown(refresh))
+	b.WriteString("\n*Press 'i' or Esc to close.*")
+	return b.String()
+}
+
+// fetchModeMarkdown says how the articles on screen got here, and what
+// each way of getting them costs in waiting and in staleness.
+func (m model) fetchModeMarkdown(refresh time.Duration) string {
+	var b strings.Builder
+	b.WriteString("## How the feed is fetched\n\n")
+	if cfg.fetchMode == fetchEager {
+		b.WriteString("- **Startup:** eager. The feed was fetched when the server started, so no " +
+			"session waits for it, at the cost of asking the feed host even while nobody is connected.\n")
+	} else {
+		b.WriteString("- **Startup:** lazy. Nothing is fetched until someone connects, so an idle " +
+			"server makes no requests, but the first session after startup waits for the feed host.\n")
+	}
+	fmt.Fprintf(&b, "- **Direct:** a fetch from the feed host is as fresh as the feed gets, but "+
+		"takes as long as the host does, up to %s. Only a cold cache fetches directly.\n", cfg.fetchTimeout)
+	fmt.Fprintf(&b, "- **Cache:** every session reads one shared copy, so opening one is instant, "+
+		"but it can be up to %s behind the feed. A failed refresh keeps the old copy rather than "+
+		"showing nothing, so it can fall further behind.\n", refresh)
+	if cfg.fallbackFeed == "" {
+		b.WriteString("- **Fallback:** none set, so while the feed host is down sessions get the " +
+			"cache or, with none, an error.\n")
+	} else {
+		fmt.Fprintf(&b, "- **Fallback:** after %d failed tries, %s apart, %s is served instead. "+
+			"Sessions keep reading, but from another source, and only once the retries have "+
+			"added their delay. The main feed is tried again first at every refresh.\n",
+			fallbackAttempts, retryDelay, cfg.fallbackFeed)
+	}
+	if m.fallback {
+		b.WriteString("\nThe main feed is failing, so this is the fallback.\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// itemFilter keeps or drops items of one feed by regular expression.
+type itemFilter struct {
+	include, exclude *regexp.Regexp
+}
+
+// compileFilters builds the per-feed filters from -include and -exclude.
+func compileFilters(include, exclude perFeed) (map[string]itemFilter, error) {
+	filters := map[string]itemFilter{}
+	for url, expr := range include {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("-include for %s: %w", url, err)
+		}
+		f := filters[url]
+		f.include = re
+		filters[url] = f
+	}
+	for url, expr := range exclude {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("-exclude for %s: %w", url, err)
+		}
+		f := filters[url]
+		f.exclude = re
+		filters[url] = f
+	}
+	return filters, nil
+}
+
+// keep reports whether item passes f: it must match include, if there is
+// one, and must not match exclude.
+func (f itemFilter) keep(item RSSItem) bool {
+	text := item.Title
+	if cfg.filterOn == filterOnAll {
+		text += "\n" + plainText(item.Description)
+	}
+	if f.include != nil && !f.include.MatchString(text) {
+		return false
+	}
+	return f.exclude == nil || !f.exclude.MatchString(text)
+}
+
+// filterItems applies feedURL's filter, if it has one, to items.
+func filterItems(feedURL string, items []RSSItem) []RSSItem {
+	f, ok := cfg.filters[feedURL]
+	if !ok {
+		return items
+	}
+	kept := items[:0:0]
+	for _, item := range items {
+		if f.keep(item) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+const (
+	filterOnTitle = "title"
+	filterOnAll   = "all"
+)
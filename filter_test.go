@@ -0,0 +1,58 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFilterItems(t *testing.T) {
+	items := testFeed().Items
+	tests := []struct {
+		name             string
+		include, exclude string
+		filterOn         string
+		want             []string
+	}{
+		{"none", "", "", filterOnTitle, []string{"Senate passes budget", "Governor signs bill"}},
+		{"include", "(?i)senate", "", filterOnTitle, []string{"Senate passes budget"}},
+		{"exclude", "", "bill$", filterOnTitle, []string{"Senate passes budget"}},
+		{"both", "Senate|Governor", "Governor", filterOnTitle, []string{"Senate passes budget"}},
+		{"title only", "next year", "", filterOnTitle, nil},
+		{"description too", "next year", "", filterOnAll, []string{"Governor signs bill"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := cfg
+			c.include, c.exclude, c.filterOn = perFeed{}, perFeed{}, tt.filterOn
+			if tt.include != "" {
+				c.include["https://example.com/feed"] = tt.include
+			}
+			if tt.exclude != "" {
+				c.exclude["https://example.com/feed"] = tt.exclude
+			}
+			var err error
+			if c.filters, err = compileFilters(c.include, c.exclude); err != nil {
+				t.Fatal(err)
+			}
+			withConfig(t, c)
+
+			var got []string
+			for _, item := range filterItems("https://example.com/feed", items) {
+				got = append(got, item.Title)
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("kept %q, want %q", got, tt.want)
+			}
+		})
+	}
+	if len(items) != 2 {
+		t.Error("filterItems modified its input")
+	}
+}
+
+func TestCompileFiltersError(t *testing.T) {
+	_, err := compileFilters(perFeed{"https://example.com/feed": "(unclosed"}, perFeed{})
+	if err == nil || !strings.Contains(err.Error(), "-include for https://example.com/feed") {
+		t.Errorf("got %v, want an error naming the flag and feed", err)
+	}
+}
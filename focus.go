@@ -0,0 +1,69 @@
+package main
+
+import (
+	"log/slog"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// focusMaxWidth caps the text column in focus mode; long lines are hard to
+// read however wide the terminal is.
+const focusMaxWidth = 72
+
+// toggleFocus shows the open article alone, without the list around it.
+func (m *model) toggleFocus() tea.Cmd {
+	if !m.showDetail {
+		return m.noArticleOpen()
+	}
+	m.focusMode = !m.focusMode
+	return nil
+}
+
+// focusRenderer keeps focus mode's glamour renderer between redraws,
+// making a new one only when the width it wraps to changes.
+type focusRenderer struct {
+	width int
+	r     *glamour.TermRenderer
+}
+
+func (f *focusRenderer) get(width int) (*glamour.TermRenderer, error) {
+	if f.r != nil && f.width == width {
+		return f.r, nil
+	}
+	r, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle("dark"),
+		glamour.WithWordWrap(width),
+	)
+	if err != nil {
+		return nil, err
+	}
+	f.width, f.r = width, r
+	return r, nil
+}
+
+// focusView renders only the selected article, centered in the window.
+func (m model) focusView() string {
+	width := min(m.width-4, focusMaxWidth)
+	if width <= 0 {
+		width = focusMaxWidth
+	}
+	r, err := m.focus.get(width)
+	if err != nil {
+		slog.Default().Error("Failed to create markdown renderer", "error", err)
+		return ""
+	}
+	md := m.detailMarkdown()
+	if s := m.dwellStatus(); s != "" {
+		md += "\n\n*" + s + "*"
+	}
+	out, err := r.Render(md)
+	if err != nil {
+		slog.Default().Error("Failed to render markdown", "error", err)
+		return ""
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Top, focusStyle.Render(out))
+}
+
+var focusStyle = lipgloss.NewStyle().Padding(1, 0)
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"mime"
+)
+
+type feedFormat int
+
+const (
+	formatUnknown feedFormat = iota
+	formatRSS
+	formatAtom
+	formatJSON
+)
+
+var formatNames = [...]string{"unknown", "RSS", "Atom", "JSON Feed"}
+
+var errNotAFeed = errors.New("response is not a feed")
+
+// detectFormat trusts a content type that names a feed format and sniffs the
+// body otherwise: plenty of feeds are served as text/html, text/plain or
+// application/octet-stream, and generic XML could be either RSS or Atom.
+func detectFormat(contentType string, body []byte) feedFormat {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/rss+xml":
+		return formatRSS
+	case "application/atom+xml":
+		return formatAtom
+	case "application/feed+json":
+		return formatJSON
+	}
+	return sniffFormat(body)
+}
+
+// sniffFormat looks at the first thing in body that isn't whitespace, a BOM,
+// an XML declaration, a comment or a doctype.
+func sniffFormat(body []byte) feedFormat {
+	body = bytes.TrimPrefix(body, []byte("\xef\xbb\xbf"))
+	if trimmed := bytes.TrimSpace(body); bytes.HasPrefix(trimmed, []byte("{")) {
+		return formatJSON
+	}
+	d := xml.NewDecoder(bytes.NewReader(body))
+	d.Strict = false
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return formatUnknown
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			switch tok.Name.Local {
+			case "rss":
+				return formatRSS
+			case "feed":
+				return formatAtom
+			}
+			return formatUnknown
+		case xml.CharData:
+			if len(bytes.TrimSpace(tok)) > 0 {
+				return formatUnknown
+			}
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import "testing"
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        feedFormat
+	}{
+		{"trusted rss", "application/rss+xml", "", formatRSS},
+		{"trusted atom", "application/atom+xml; charset=utf-8", "", formatAtom},
+		{"rss as html", "text/html", `<?xml version="1.0"?><rss version="2.0"></rss>`, formatRSS},
+		{"rss with bom and comment", "text/xml", "\xef\xbb\xbf<!-- hi --><rss></rss>", formatRSS},
+		{"atom as octet-stream", "application/octet-stream", `<feed xmlns="http://www.w3.org/2005/Atom"></feed>`, formatAtom},
+		{"json as plain text", "text/plain", `  {"version": "https://jsonfeed.org/version/1.1"}`, formatJSON},
+		{"html page", "text/html", "<!DOCTYPE html><html></html>", formatUnknown},
+		{"plain text", "text/plain", "hello", formatUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := detectFormat(tt.contentType, []byte(tt.body)); got != tt.want {
+				t.Errorf("got %s, want %s", formatNames[got], formatNames[tt.want])
+			}
+		})
+	}
+}
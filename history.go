@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+)
+
+// maxHistory bounds how many read items we remember per user.
+const maxHistory = 1000
+
+// readEntry records that the user opened an item.
+type readEntry struct {
+	GUID   string    `json:"guid"`
+	Title  string    `json:"title"`
+	Link   string    `json:"link"`
+	Opened time.Time `json:"opened"`
+}
+
+// key identifies the item the same way dedupItems does.
+func (r rssListItem) key() string {
+	if r.rss.Id != "" {
+		return r.rss.Id
+	}
+	return r.link
+}
+
+// recordRead moves item to the end of the history with a fresh timestamp.
+func (m *model) recordRead(item rssListItem, now time.Time) tea.Cmd {
+	k := item.key()
+	// Build a new slice: saveState hands the old one to another goroutine.
+	h := make([]readEntry, 0, len(m.state.History)+1)
+	for _, e := range m.state.History {
+		if e.GUID != k {
+			h = append(h, e)
+		}
+	}
+	h = append(h, readEntry{GUID: k, Title: item.title, Link: item.link, Opened: now})
+	if len(h) > maxHistory {
+		h = h[len(h)-maxHistory:]
+	}
+	m.state.History = h
+	audit.record(auditEvent{Event: "open", User: m.user, Title: item.title, Link: item.link})
+	openHook.fire(newHookEvent(item, m.user, now))
+	return m.saveState()
+}
+
+func encodeHistory(h []readEntry, format string) ([]byte, error) {
+	if format == "json" {
+		return json.MarshalIndent(h, "", "  ")
+	}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"guid", "title", "link", "opened"})
+	for _, e := range h {
+		w.Write([]string{e.GUID, e.Title, e.Link, e.Opened.Format(time.RFC3339)})
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// exportHistory writes a member's history to -export-dir, or copies it to
+// the client's clipboard for guests and when no directory is configured.
+func (m *model) exportHistory(format string) tea.Cmd {
+	if len(m.state.History) == 0 {
+		return m.list.NewStatusMessage("Nothing read yet")
+	}
+	data, err := encodeHistory(m.state.History, format)
+	if err != nil {
+		log.Error("Failed to encode history", "error", err)
+		return m.list.NewStatusMessage("Export failed")
+	}
+	size := formatSize(len(data))
+	// Guests don't get to leave files on the server either.
+	if cfg.exportDir == "" || !m.member {
+		if m.out != nil {
+			if _, err := osc52.New(string(data)).WriteTo(m.out); err != nil {
+				log.Error("Failed to copy history", "error", err)
+				return m.list.NewStatusMessage("Export failed")
+			}
+		}
+		return m.list.NewStatusMessage(fmt.Sprintf("Copied %d reads (%s) to the clipboard", len(m.state.History), size))
+	}
+	user := m.user
+	if user == "" {
+		user = "anonymous"
+	} else {
+		user = user[:12]
+	}
+	name := fmt.Sprintf("history-%s-%s.%s", user, time.Now().UTC().Format("20060102T150405Z"), format)
+	path := filepath.Join(cfg.exportDir, name)
+	if err := os.MkdirAll(cfg.exportDir, 0o700); err != nil {
+		log.Error("Failed to export history", "error", err)
+		return m.list.NewStatusMessage("Export failed")
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		log.Error("Failed to export history", "error", err)
+		return m.list.NewStatusMessage("Export failed")
+	}
+	return m.list.NewStatusMessage(fmt.Sprintf("Exported %d reads (%s) to %s", len(m.state.History), size, path))
+}
+
+func formatSize(n int) string {
+	if n < 1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+	return fmt.Sprintf("%.1f kB", float64(n)/1024)
+}
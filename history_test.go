@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecordRead(t *testing.T) {
+	m := newModel(testFeed(), userState{}, 80, 24)
+	items := m.list.Items()
+	budget, bill := items[0].(rssListItem), items[1].(rssListItem)
+	t0 := time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC)
+
+	m.recordRead(budget, t0)
+	m.recordRead(bill, t0.Add(time.Minute))
+	m.recordRead(budget, t0.Add(2*time.Minute))
+
+	h := m.state.History
+	if len(h) != 2 {
+		t.Fatalf("got %d entries, want 2", len(h))
+	}
+	if h[0].GUID != "bill-1" || h[1].GUID != "budget-1" {
+		t.Errorf("order = %s, %s; want bill-1, budget-1", h[0].GUID, h[1].GUID)
+	}
+	if !h[1].Opened.Equal(t0.Add(2 * time.Minute)) {
+		t.Errorf("reopened item kept its old timestamp %v", h[1].Opened)
+	}
+}
+
+func TestExportHistory(t *testing.T) {
+	dir := t.TempDir()
+	c := cfg
+	c.exportDir = dir
+	withConfig(t, c)
+
+	m := newModel(testFeed(), userState{}, 80, 24)
+	m.recordRead(m.list.Items()[0].(rssListItem), time.Date(2025, 7, 1, 9, 0, 0, 0, time.UTC))
+	m.exportHistory("csv")
+	if files, _ := filepath.Glob(filepath.Join(dir, "*")); len(files) != 0 {
+		t.Fatalf("a guest exported to the server: %q", files)
+	}
+	m.member = true
+	m.exportHistory("csv")
+
+	files, _ := filepath.Glob(filepath.Join(dir, "history-anonymous-*.csv"))
+	if len(files) != 1 {
+		t.Fatalf("got exports %q", files)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "guid,title,link,opened\nbudget-1,Senate passes budget,https://example.com/budget,2025-07-01T09:00:00Z\n"
+	if string(data) != want {
+		t.Errorf("export =\n%s\nwant\n%s", data, want)
+	}
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// openHook is told about every article opened; nil when neither
+// -open-hook-url nor -open-hook-exec is set.
+var openHook *hookRunner
+
+// maxHooks bounds the hooks running at once. Opening articles is up to
+// whoever is connected, so past this they are dropped rather than queued.
+const maxHooks = 4
+
+// hookEvent is what a hook is told about an opened article. The POST body
+// is its JSON; a command gets the link as its argument and every field in
+// the environment.
+type hookEvent struct {
+	Time time.Time `json:"time"`
+	// User is the userKey hash, as in the audit log.
+	User      string    `json:"user,omitempty"`
+	GUID      string    `json:"guid"`
+	Title     string    `json:"title"`
+	Link      string    `json:"link"`
+	Author    string    `json:"author,omitempty"`
+	Published time.Time `json:"published,omitzero"`
+}
+
+func newHookEvent(item rssListItem, user string, now time.Time) hookEvent {
+	return hookEvent{
+		Time:      now,
+		User:      user,
+		GUID:      item.key(),
+		Title:     item.title,
+		Link:      item.link,
+		Author:    item.author,
+		Published: item.published,
+	}
+}
+
+// env is e as NEWS_* variables, for -open-hook-exec.
+func (e hookEvent) env() []string {
+	env := []string{
+		"NEWS_TIME=" + e.Time.Format(time.RFC3339),
+		"NEWS_USER=" + e.User,
+		"NEWS_GUID=" + e.GUID,
+		"NEWS_TITLE=" + e.Title,
+		"NEWS_LINK=" + e.Link,
+		"NEWS_AUTHOR=" + e.Author,
+	}
+	if !e.Published.IsZero() {
+		env = append(env, "NEWS_PUBLISHED="+e.Published.Format(time.RFC3339))
+	}
+	return env
+}
+
+// hookRunner posts each event to url, or runs command with it, in the
+// background and giving up after timeout.
+type hookRunner struct {
+	url     string
+	command string
+	timeout time.Duration
+	running chan struct{}
+	// client is made up front, so hooks still running never read cfg.
+	client *http.Client
+}
+
+// newHookRunner returns nil if there is nothing to run.
+func newHookRunner(url, command string, timeout time.Duration) *hookRunner {
+	if url == "" && command == "" {
+		return nil
+	}
+	h := &hookRunner{url: url, command: command, timeout: timeout, running: make(chan struct{}, maxHooks)}
+	if url != "" {
+		h.client = newFeedClient(url)
+	}
+	return h
+}
+
+// fire starts the hook for e and returns at once, reporting whether it
+// started. Failures are logged: a hook is never the session's problem.
+func (h *hookRunner) fire(e hookEvent) bool {
+	if h == nil {
+		return false
+	}
+	select {
+	case h.running <- struct{}{}:
+	default:
+		log.Warn("Dropping open hook, too many still running", "link", e.Link, "max", maxHooks)
+		return false
+	}
+	go func() {
+		defer func() { <-h.running }()
+		ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+		defer cancel()
+		if err := h.run(ctx, e); err != nil {
+			log.Error("Open hook failed", "link", e.Link, "error", err)
+		}
+	}()
+	return true
+}
+
+func (h *hookRunner) run(ctx context.Context, e hookEvent) error {
+	if h.command != "" {
+		// No shell, so nothing in the feed's title or link is ever
+		// interpreted as a command.
+		cmd := exec.CommandContext(ctx, h.command, e.Link)
+		cmd.Env = append(os.Environ(), e.env()...)
+		return cmd.Run()
+	}
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s returned %s", h.url, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func hookItem() rssListItem {
+	return rssListItem{
+		title:     "Senate passes budget",
+		link:      "https://example.com/budget",
+		author:    "Jane Reporter",
+		published: time.Date(2025, 6, 2, 9, 30, 0, 0, time.UTC),
+		rss:       RSSItem{Id: "budget-1"},
+	}
+}
+
+func TestOpenHookURL(t *testing.T) {
+	got := make(chan hookEvent, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var e hookEvent
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("got %s with Content-Type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&e); err != nil {
+			t.Error(err)
+		}
+		got <- e
+	}))
+	defer srv.Close()
+
+	h := newHookRunner(srv.URL, "", time.Second)
+	now := time.Now()
+	if !h.fire(newHookEvent(hookItem(), "abc", now)) {
+		t.Fatal("hook didn't start")
+	}
+	select {
+	case e := <-got:
+		if e.GUID != "budget-1" || e.Title != "Senate passes budget" || e.Link != "https://example.com/budget" ||
+			e.Author != "Jane Reporter" || e.User != "abc" || !e.Published.Equal(hookItem().published) {
+			t.Errorf("got %+v", e)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("hook never posted")
+	}
+}
+
+func TestOpenHookExec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	script := filepath.Join(dir, "hook.sh")
+	body := "#!/bin/sh\nprintf '%s\\n%s\\n%s\\n' \"$1\" \"$NEWS_TITLE\" \"$NEWS_AUTHOR\" > " + out + ".tmp && mv " + out + ".tmp " + out + "\n"
+	if err := os.WriteFile(script, []byte(body), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	item := hookItem()
+	item.title = "Budget; rm -rf / $(reboot)"
+
+	h := newHookRunner("", script, 5*time.Second)
+	if !h.fire(newHookEvent(item, "", time.Now())) {
+		t.Fatal("hook didn't start")
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, err := os.ReadFile(out)
+		if err == nil {
+			want := "https://example.com/budget\nBudget; rm -rf / $(reboot)\nJane Reporter\n"
+			if string(data) != want {
+				t.Errorf("got %q, want %q", data, want)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("hook never ran")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestOpenHookLimit(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	h := newHookRunner(srv.URL, "", 5*time.Second)
+	for i := range maxHooks {
+		if !h.fire(newHookEvent(hookItem(), "", time.Now())) {
+			t.Fatalf("hook %d didn't start", i)
+		}
+	}
+	if h.fire(newHookEvent(hookItem(), "", time.Now())) {
+		t.Error("a hook past the limit started")
+	}
+	if (*hookRunner)(nil).fire(hookEvent{}) {
+		t.Error("a nil runner started a hook")
+	}
+	if newHookRunner("", "", time.Second) != nil {
+		t.Error("newHookRunner with nothing to run isn't nil")
+	}
+}
+
+func TestOpenHookConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		command string
+		want    string
+	}{
+		{"both", "https://example.com/hook", "true", "can't both"},
+		{"not http", "ftp://example.com/hook", "", "http or https"},
+		{"missing program", "", "no-such-hook-program", "-open-hook-exec"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := cfg
+			c.openHookURL, c.openHookExec = tt.url, tt.command
+			err := c.validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want an error mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	gossh "golang.org/x/crypto/ssh"
+)
+
+// errNoHostKeys is returned when a host key directory holds no usable keys.
+var errNoHostKeys = errors.New("no host keys found")
+
+// loadHostKeys parses every private key in dir, skipping .pub files and
+// anything that isn't a regular file. The server offers at most one key per
+// algorithm, so when several share one the primary wins, and otherwise the
+// last file by name. The primary, if named, must exist; it comes first.
+//
+// To rotate, add the new key under a different algorithm (say ed25519
+// alongside an old rsa key): clients that already trust the old key keep
+// negotiating it while new clients can pin the new one.
+func loadHostKeys(dir, primary string) ([]gossh.Signer, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	byType := map[string]gossh.Signer{}
+	names := map[string]string{}
+	for _, e := range entries {
+		if !e.Type().IsRegular() || strings.HasSuffix(e.Name(), ".pub") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		signer, err := gossh.ParsePrivateKey(data)
+		if err != nil {
+			return nil, fmt.Errorf("host key %s: %w", e.Name(), err)
+		}
+		typ := signer.PublicKey().Type()
+		if prev, ok := names[typ]; ok {
+			dropped, kept := prev, e.Name()
+			if prev == primary {
+				dropped, kept = kept, dropped
+			}
+			log.Warn("Ignoring host key of an already offered type", "key", dropped, "kept", kept, "type", typ)
+			if kept == prev {
+				continue
+			}
+		}
+		byType[typ] = signer
+		names[typ] = e.Name()
+	}
+	if len(byType) == 0 {
+		return nil, fmt.Errorf("%w in %s", errNoHostKeys, dir)
+	}
+	types := make([]string, 0, len(byType))
+	for typ := range byType {
+		types = append(types, typ)
+	}
+	slices.SortFunc(types, func(a, b string) int {
+		switch {
+		case names[a] == primary:
+			return -1
+		case names[b] == primary:
+			return 1
+		}
+		return strings.Compare(names[a], names[b])
+	})
+	if primary != "" && names[types[0]] != primary {
+		return nil, fmt.Errorf("primary host key %s not found in %s", primary, dir)
+	}
+	signers := make([]gossh.Signer, len(types))
+	for i, typ := range types {
+		signers[i] = byType[typ]
+		log.Info("Offering host key", "key", names[typ], "type", typ,
+			"fingerprint", gossh.FingerprintSHA256(signers[i].PublicKey()), "primary", names[typ] == primary)
+	}
+	return signers, nil
+}
+
+// withHostKeys adds signers to the server in place of a single key path.
+func withHostKeys(signers []gossh.Signer) ssh.Option {
+	return func(s *ssh.Server) error {
+		for _, signer := range signers {
+			s.AddHostKey(signer)
+		}
+		return nil
+	}
+}
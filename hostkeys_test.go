@@ -0,0 +1,76 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	gossh "golang.org/x/crypto/ssh"
+)
+
+func writeHostKey(t *testing.T, dir, name string, priv any) gossh.PublicKey {
+	t.Helper()
+	block, err := gossh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), pem.EncodeToMemory(block), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	signer, err := gossh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signer.PublicKey()
+}
+
+func TestLoadHostKeys(t *testing.T) {
+	dir := t.TempDir()
+	_, edOld, _ := ed25519.GenerateKey(rand.Reader)
+	_, edNew, _ := ed25519.GenerateKey(rand.Reader)
+	ec, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	oldPub := writeHostKey(t, dir, "a_ed25519", edOld)
+	newPub := writeHostKey(t, dir, "b_ed25519", edNew)
+	ecPub := writeHostKey(t, dir, "new_ecdsa", ec)
+	if err := os.WriteFile(filepath.Join(dir, "new_ecdsa.pub"), gossh.MarshalAuthorizedKey(ecPub), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	same := func(a, b gossh.PublicKey) bool { return string(a.Marshal()) == string(b.Marshal()) }
+	tests := []struct {
+		primary string
+		want    []gossh.PublicKey
+	}{
+		{"", []gossh.PublicKey{newPub, ecPub}},
+		{"a_ed25519", []gossh.PublicKey{oldPub, ecPub}},
+		{"new_ecdsa", []gossh.PublicKey{ecPub, newPub}},
+	}
+	for _, tt := range tests {
+		signers, err := loadHostKeys(dir, tt.primary)
+		if err != nil {
+			t.Fatalf("primary %q: %v", tt.primary, err)
+		}
+		if len(signers) != len(tt.want) {
+			t.Fatalf("primary %q: got %d keys, want %d", tt.primary, len(signers), len(tt.want))
+		}
+		for i, want := range tt.want {
+			if !same(signers[i].PublicKey(), want) {
+				t.Errorf("primary %q: key %d is %s, want %s", tt.primary, i,
+					gossh.FingerprintSHA256(signers[i].PublicKey()), gossh.FingerprintSHA256(want))
+			}
+		}
+	}
+
+	if _, err := loadHostKeys(dir, "missing"); err == nil {
+		t.Error("missing primary: got nil error")
+	}
+	if _, err := loadHostKeys(t.TempDir(), ""); !errors.Is(err, errNoHostKeys) {
+		t.Errorf("empty dir: got %v, want errNoHostKeys", err)
+	}
+}
@@ -0,0 +1,375 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"golang.org/x/net/html"
+)
+
+// sanitizePolicies are the named policies an operator can assign to a feed
+// with -sanitize. Descriptions come straight from the feed, so every one of
+// them drops scripts, styles and event handlers.
+var sanitizePolicies = map[string]*bluemonday.Policy{
+	// strict keeps the text only.
+	"strict": bluemonday.StrictPolicy(),
+	// links keeps basic formatting, lists, tables, quotes, preformatted
+	// text and links but drops images.
+	"links": linksPolicy(),
+	// ugc keeps what bluemonday considers safe user content, images included.
+	"ugc": bluemonday.UGCPolicy(),
+}
+
+const defaultSanitizePolicy = "links"
+
+func linksPolicy() *bluemonday.Policy {
+	p := bluemonday.NewPolicy()
+	p.AllowStandardURLs()
+	p.AllowAttrs("href").OnElements("a")
+	p.AllowElements("p", "br", "b", "strong", "i", "em", "code", "pre", "blockquote")
+	p.AllowTables()
+	p.AllowLists()
+	return p
+}
+
+// sanitizeDescription cleans an item description with the policy configured
+// for feedURL and converts what is left to markdown for glamour, which would
+// otherwise drop inline HTML along with any link targets.
+func sanitizeDescription(feedURL, desc string) string {
+	name, ok := cfg.sanitize[feedURL]
+	if !ok {
+		name = defaultSanitizePolicy
+	}
+	return htmlToMarkdown(sanitizePolicies[name].Sanitize(desc))
+}
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// markdownEscaper backslash-escapes the characters that would let feed
+// text make markup of its own, such as a link to a javascript: URL.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`,
+	`[`, `\[`, `]`, `\]`, `(`, `\(`, `)`, `\)`, `<`, `\<`, `>`, `\>`,
+)
+
+// escapeMarkdown makes s show as the text it is.
+func escapeMarkdown(s string) string { return markdownEscaper.Replace(s) }
+
+// linkDest makes a vetted URL safe to put in a markdown link's (...): a
+// space or parenthesis in it would otherwise end the link early and let
+// the rest be read as markup.
+func linkDest(u string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E").Replace(u)
+}
+
+// htmlToMarkdown converts the small subset of HTML the sanitize policies let
+// through. Anything else is reduced to its text.
+func htmlToMarkdown(s string) string {
+	var (
+		hrefs []string
+		// blocks holds what is being written: the whole text, then any
+		// blockquote, table cell or code that can only be written out once
+		// it closes.
+		blocks = []*mdBlock{{}}
+		tables []*mdTable
+		// pre counts open <pre>s, inside which text is kept as is.
+		pre      int
+		preStart bool
+	)
+	w := func() *mdBlock { return blocks[len(blocks)-1] }
+	pop := func() *mdBlock {
+		top := w()
+		blocks = blocks[:len(blocks)-1]
+		return top
+	}
+	push := func(kind string) {
+		blocks = append(blocks, &mdBlock{kind: kind, tables: len(tables)})
+	}
+	// ownsTable reports whether the innermost table belongs to the top
+	// block: it was opened inside it, or the block is one of its cells.
+	// Table tags for a table outside the block are ignored, so they can
+	// never close it from within.
+	ownsTable := func() bool {
+		top := w()
+		return len(tables) > top.tables || (top.kind == "cell" && len(tables) > 0 && top.tables == len(tables))
+	}
+	var closeBlock func()
+	// closeInner closes any code spans opened since the innermost table
+	// and then the top block, if it is kind.
+	closeInner := func(kind string) {
+		for w().kind == "code" && w().tables == len(tables) {
+			closeBlock()
+		}
+		if kind != "" && w().kind == kind {
+			closeBlock()
+		}
+	}
+	closeCell := func() {
+		closeInner("")
+		if top := w(); top.kind == "cell" && top.tables == len(tables) {
+			closeBlock()
+		}
+	}
+	closeRow := func() {
+		closeCell()
+		if t := tables[len(tables)-1]; len(t.row) > 0 {
+			t.rows, t.row = append(t.rows, t.row), nil
+		}
+	}
+	closeTable := func() {
+		closeRow()
+		t := tables[len(tables)-1]
+		tables = tables[:len(tables)-1]
+		w().WriteString("\n\n" + t.markdown() + "\n")
+	}
+	// closeBlock writes the top block out into the one around it, first
+	// closing any tables left open inside it. The root is never closed.
+	closeBlock = func() {
+		for len(tables) > w().tables {
+			closeTable()
+		}
+		b := pop()
+		switch b.kind {
+		case "code":
+			w().WriteString(codeSpan(strings.ReplaceAll(b.String(), "\n", " ")))
+		case "pre":
+			pre = 0
+			w().WriteString("\n\n" + codeBlock(b.String(), "") + "\n")
+		case "cell":
+			cell := strings.Join(strings.Fields(b.String()), " ")
+			t := tables[len(tables)-1]
+			t.row = append(t.row, strings.ReplaceAll(cell, "|", `\|`))
+		case "quote":
+			text := strings.TrimSpace(blankLines.ReplaceAllString(b.String(), "\n\n"))
+			lines := strings.Split(text, "\n")
+			for i, l := range lines {
+				lines[i] = strings.TrimRight("> "+l, " ")
+			}
+			w().WriteString("\n\n" + strings.Join(lines, "\n") + "\n\n")
+		}
+	}
+
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		tok := z.Token()
+		b := w()
+		if pre > 0 && tok.Data != "pre" {
+			switch {
+			case tt == html.TextToken:
+				text := tok.Data
+				if preStart {
+					// As in a browser, a newline right after <pre> is
+					// dropped.
+					text = strings.TrimPrefix(text, "\n")
+				}
+				b.WriteString(text)
+				preStart = false
+			case tok.Data == "br":
+				b.WriteString("\n")
+			}
+			continue
+		}
+		switch tt {
+		case html.TextToken:
+			if len(tables) > 0 && b.kind != "cell" && strings.TrimSpace(tok.Data) == "" {
+				// The indentation between table tags.
+				continue
+			}
+			if b.kind == "code" {
+				b.WriteString(tok.Data)
+			} else {
+				b.WriteString(escapeMarkdown(tok.Data))
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			switch tok.Data {
+			case "p", "div":
+				b.WriteString("\n\n")
+			case "br":
+				b.WriteString("  \n")
+			case "b", "strong":
+				b.WriteString("**")
+			case "i", "em":
+				b.WriteString("*")
+			case "code":
+				push("code")
+			case "ul", "ol":
+				b.WriteString("\n")
+			case "li":
+				b.WriteString("\n- ")
+			case "pre":
+				if pre == 0 {
+					closeInner("")
+					push("pre")
+				}
+				pre++
+				preStart = true
+			case "blockquote":
+				push("quote")
+			case "table":
+				tables = append(tables, &mdTable{})
+			case "tr":
+				// </tr> and </td> are optional, so a new row or cell
+				// closes the last one.
+				if ownsTable() {
+					closeRow()
+				}
+			case "td", "th":
+				if ownsTable() {
+					closeCell()
+					push("cell")
+				}
+			case "a":
+				// The policies vet hrefs too, but not against -link-schemes.
+				hrefs = append(hrefs, safeLink(attr(tok, "href")))
+				b.WriteString("[")
+			case "img":
+				if src := safeLink(attr(tok, "src")); src != "" {
+					fmt.Fprintf(b, "![%s](%s)", escapeMarkdown(attr(tok, "alt")), linkDest(src))
+				} else {
+					b.WriteString(escapeMarkdown(attr(tok, "alt")))
+				}
+			}
+		case html.EndTagToken:
+			switch tok.Data {
+			case "p", "div", "ul", "ol":
+				b.WriteString("\n\n")
+			case "b", "strong":
+				b.WriteString("**")
+			case "i", "em":
+				b.WriteString("*")
+			case "code":
+				closeInner("")
+			case "pre":
+				if pre > 0 {
+					if pre--; pre == 0 {
+						closeInner("pre")
+					}
+				}
+			case "blockquote":
+				closeInner("quote")
+			case "td", "th":
+				if ownsTable() {
+					closeCell()
+				}
+			case "tr":
+				if ownsTable() {
+					closeRow()
+				}
+			case "table":
+				if ownsTable() {
+					closeTable()
+				}
+			case "a":
+				var href string
+				if n := len(hrefs); n > 0 {
+					href, hrefs = hrefs[n-1], hrefs[:n-1]
+				}
+				if href == "" {
+					b.WriteString("]")
+				} else {
+					fmt.Fprintf(b, "](%s)", linkDest(href))
+				}
+			}
+		}
+	}
+	// Close whatever the markup left open, innermost first.
+	for len(blocks) > 1 {
+		closeBlock()
+	}
+	for len(tables) > 0 {
+		closeTable()
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(blocks[0].String(), "\n\n"))
+}
+
+// codeSpan and codeBlock fence text as code with more backticks than it
+// holds in a row, so nothing in it can end the fence early.
+func codeSpan(text string) string {
+	fence := strings.Repeat("`", longestRun(text, '`')+1)
+	if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+		text = " " + text + " "
+	}
+	return fence + text + fence
+}
+
+func codeBlock(text, info string) string {
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	fence := strings.Repeat("`", max(3, longestRun(text, '`')+1))
+	return fence + info + "\n" + text + fence + "\n"
+}
+
+// longestRun is the length of the longest run of c in s.
+func longestRun(s string, c byte) int {
+	longest, run := 0, 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == c {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return longest
+}
+
+// mdBlock collects a blockquote, table cell, code or preformatted text.
+type mdBlock struct {
+	kind string
+	// tables is how many tables were open when the block started.
+	tables int
+	strings.Builder
+}
+
+// mdTable is a table being converted, row by row.
+type mdTable struct {
+	rows [][]string
+	row  []string
+}
+
+// markdown is the table with its first row as the header, which markdown
+// tables must have, and short rows padded out.
+func (t *mdTable) markdown() string {
+	width := 0
+	for _, r := range t.rows {
+		width = max(width, len(r))
+	}
+	if width == 0 {
+		return ""
+	}
+	var b strings.Builder
+	line := func(cells []string) {
+		b.WriteString("|")
+		for i := range width {
+			cell := ""
+			if i < len(cells) {
+				cell = cells[i]
+			}
+			b.WriteString(" " + cell + " |")
+		}
+		b.WriteString("\n")
+	}
+	line(t.rows[0])
+	line(slices.Repeat([]string{"---"}, width))
+	for _, r := range t.rows[1:] {
+		line(r)
+	}
+	return b.String()
+}
+
+func attr(tok html.Token, name string) string {
+	for _, a := range tok.Attr {
+		if a.Key == name {
+			return a.Val
+		}
+	}
+	return ""
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestSanitizeDescription(t *testing.T) {
+	const feed = "https://example.com/rss"
+	c := cfg
+	c.sanitize = perFeed{"https://strict.example.com/rss": "strict"}
+	withConfig(t, c)
+
+	tests := []struct {
+		name string
+		feed string
+		in   string
+		want string
+	}{
+		{
+			name: "default keeps links",
+			feed: feed,
+			in:   `<p>Read <a href="https://example.com/a">more</a></p>`,
+			want: "Read [more](https://example.com/a)",
+		},
+		{
+			name: "default drops scripts and images",
+			feed: feed,
+			in:   `<p>Hi<script>alert(1)</script><img src="x.png"></p>`,
+			want: "Hi",
+		},
+		{
+			name: "formatting",
+			feed: feed,
+			in:   `<b>Big</b> and <em>small</em><ul><li>one</li><li>two</li></ul>`,
+			want: "**Big** and *small*\n\n- one\n- two",
+		},
+		{
+			name: "strict keeps text only",
+			feed: "https://strict.example.com/rss",
+			in:   `<p>Read <a href="https://example.com/a">more</a></p>`,
+			want: "Read more",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sanitizeDescription(tt.feed, tt.in); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeDescriptionBlocks(t *testing.T) {
+	src, err := os.ReadFile("testdata/tables.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := sanitizeDescription("https://example.com/rss", string(src))
+	for _, want := range []string{
+		"| Candidate | Party | Poll |\n| --- | --- | --- |\n",
+		`| Alvarez | Progressive \| Labor | **41%** |`,
+		"| Undecided |  |  |",
+		"> We are not done yet.\n>\n> Not by a long way.",
+		"```\nSeats   2021  2025\n-----   ----  ----\n",
+		"`turnout_v2`",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in:\n%s", want, got)
+		}
+	}
+
+	m := model{renders: map[string]string{}}
+	out, err := m.render(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if w := lipgloss.Width(line); w > detailWrap {
+			t.Errorf("rendered line is %d wide, over the modal's %d: %q", w, detailWrap, line)
+		}
+	}
+}
+
+func TestSanitizeDescriptionUnclosed(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{`<p><code>x <blockquote>quoted <table><tr><td>cell`, []string{"quoted", "| cell |"}},
+		{`<table><tr><td><blockquote>in a cell</table>after`, []string{"in a cell", "after"}},
+		{`<blockquote><code>a</blockquote>b`, []string{"a", "b"}},
+		{`<table><tr><td><table><tr><td>inner</td></tr></table>outer`, []string{"inner", "outer"}},
+		{"<p><code>x blockquote\x14quoted <table><tr><td>ce", []string{"quoted", "ce"}},
+		{`<pre>x<blockquote>y</pre>`, []string{"x", "y"}},
+		{`</td></tr></table></blockquote></code></pre>text`, []string{"text"}},
+	}
+	for _, tt := range tests {
+		got := sanitizeDescription("https://example.com/rss", tt.in)
+		for _, want := range tt.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("%q: missing %q in:\n%s", tt.in, want, got)
+			}
+		}
+	}
+}
+
+func FuzzHTMLToMarkdown(f *testing.F) {
+	for _, seed := range []string{
+		`<p><code>x <blockquote>quoted <table><tr><td>cell`,
+		`<table><tr><td><blockquote><td>x</table><pre>y<code>`,
+		`<code><pre>a</code></pre><blockquote><table><tr><th>h`,
+		`</blockquote></td></tr></table></pre></code>`,
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		htmlToMarkdown(s)
+	})
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/ssh"
+	gossh "golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// anonymousName greets users we have no name for.
+const anonymousName = "reader"
+
+// agentKeyComment returns the comment on the key the user logged in with, as
+// reported by their forwarded agent. The SSH handshake never carries key
+// comments, so this only works for clients connecting with ssh -A. We only
+// ever List the agent's keys; nothing is signed and nothing is kept.
+func agentKeyComment(s ssh.Session) string {
+	if !ssh.AgentRequested(s) || s.PublicKey() == nil {
+		return ""
+	}
+	conn, ok := s.Context().Value(ssh.ContextKeyConn).(gossh.Conn)
+	if !ok {
+		return ""
+	}
+	ch, reqs, err := conn.OpenChannel("auth-agent@openssh.com", nil)
+	if err != nil {
+		return ""
+	}
+	defer ch.Close()
+	go gossh.DiscardRequests(reqs)
+
+	type result struct {
+		keys []*agent.Key
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		keys, err := agent.NewClient(ch).List()
+		done <- result{keys, err}
+	}()
+	var r result
+	select {
+	case r = <-done:
+	case <-time.After(2 * time.Second):
+		return ""
+	}
+	if r.err != nil {
+		return ""
+	}
+	want := s.PublicKey().Marshal()
+	for _, k := range r.keys {
+		if bytes.Equal(k.Blob, want) {
+			return k.Comment
+		}
+	}
+	return ""
+}
+
+// displayName turns a key comment into something fit to print: the part
+// before any @host, without control characters, and not too long.
+func displayName(comment string) string {
+	name, _, _ := strings.Cut(comment, "@")
+	name = strings.TrimSpace(printable(name))
+	if r := []rune(name); len(r) > 32 {
+		name = string(r[:32])
+	}
+	if name == "" {
+		return anonymousName
+	}
+	return name
+}
@@ -0,0 +1,19 @@
+package main
+
+import "testing"
+
+func TestDisplayName(t *testing.T) {
+	tests := []struct{ comment, want string }{
+		{"ivan@laptop", "ivan"},
+		{"Ivan Ivanov", "Ivan Ivanov"},
+		{"", anonymousName},
+		{"@host", anonymousName},
+		{"evil\x1b[2Jname", "evil[2Jname"},
+		{"an extremely long key comment that goes on and on", "an extremely long key comment th"},
+	}
+	for _, tt := range tests {
+		if got := displayName(tt.comment); got != tt.want {
+			t.Errorf("displayName(%q) = %q, want %q", tt.comment, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// action is something the user can do. The registry below drives key
+// handling, the help view and the command palette, so an action added here
+// shows up everywhere.
+type action struct {
+	key key.Binding
+	run func(m *model) tea.Cmd
+	// short lists the action in the short help under the list too.
+	short bool
+	// listKey marks keys the list already handles and shows in its help;
+	// the action is only there so the palette can reach it.
+	listKey bool
+	// detailOnly marks keys that only mean this in the detail view.
+	// Elsewhere they go to the list, which pages with f and space.
+	detailOnly bool
+	// debug marks actions that only exist with -debug.
+	debug bool
+}
+
+// available reports whether a can be used in this server's configuration.
+func (a action) available() bool {
+	return !a.debug || cfg.debug
+}
+
+var actions = []action{
+	{
+		key:     key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search")),
+		run:     func(m *model) tea.Cmd { return m.sendListKey('/') },
+		listKey: true,
+	},
+	{
+		key:   key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "open article")),
+		run:   (*model).openSelected,
+		short: true,
+	},
+	{
+		key:        key.NewBinding(key.WithKeys("o"), key.WithHelp("o", "open article in browser")),
+		run:        (*model).openLink,
+		detailOnly: true,
+	},
+	{
+		key:        key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "focus mode")),
+		run:        (*model).toggleFocus,
+		detailOnly: true,
+	},
+	{
+		key:        key.NewBinding(key.WithKeys("r"), key.WithHelp("r", "cycle article view")),
+		run:        (*model).cycleDetailMode,
+		detailOnly: true,
+	},
+	{
+		key:        key.NewBinding(key.WithKeys(" "), key.WithHelp("space", "pause auto-advance")),
+		run:        (*model).toggleDwell,
+		detailOnly: true,
+	},
+	{
+		key:   key.NewBinding(key.WithKeys("a"), key.WithHelp("a", "toggle author")),
+		run:   (*model).toggleAuthor,
+		short: true,
+	},
+	{
+		key: key.NewBinding(key.WithKeys("A"), key.WithHelp("A", "only this author")),
+		run: (*model).toggleAuthorFilter,
+	},
+	{
+		key: key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "toggle recency colors")),
+		run: (*model).toggleAgeTint,
+	},
+	{
+		key:   key.NewBinding(key.WithKeys("p"), key.WithHelp("p", "peek at headlines")),
+		run:   (*model).togglePeek,
+		short: true,
+	},
+	{
+		key: key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "group by day")),
+		run: (*model).toggleDays,
+	},
+	{
+		key: key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "next item with media")),
+		run: (*model).nextMedia,
+	},
+	{
+		key: key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "quick look")),
+		run: (*model).toggleQuickLook,
+	},
+	{
+		key: key.NewBinding(key.WithKeys("D"), key.WithHelp("D", "show what changed")),
+		run: (*model).showChanges,
+	},
+	{
+		key: key.NewBinding(key.WithKeys("i"), key.WithHelp("i", "feed info")),
+		run: (*model).toggleInfo,
+	},
+	{
+		key: key.NewBinding(key.WithKeys("+", "="), key.WithHelp("+", "more description lines")),
+		run: func(m *model) tea.Cmd { return m.changeDescLines(1) },
+	},
+	{
+		key: key.NewBinding(key.WithKeys("-"), key.WithHelp("-", "fewer description lines")),
+		run: func(m *model) tea.Cmd { return m.changeDescLines(-1) },
+	},
+	{
+		key: key.NewBinding(key.WithKeys("L"), key.WithHelp("L", "toggle lite mode")),
+		run: (*model).toggleLite,
+	},
+	{
+		key: key.NewBinding(key.WithKeys("H"), key.WithHelp("H", "copy feed homepage")),
+		run: (*model).visitHomepage,
+	},
+	{
+		key: key.NewBinding(key.WithKeys("W"), key.WithHelp("W", "toggle welcome name")),
+		run: (*model).toggleName,
+	},
+	{
+		key: key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "export history as JSON")),
+		run: func(m *model) tea.Cmd { return m.exportHistory("json") },
+	},
+	{
+		key: key.NewBinding(key.WithKeys("E"), key.WithHelp("E", "export history as CSV")),
+		run: func(m *model) tea.Cmd { return m.exportHistory("csv") },
+	},
+	{
+		key: key.NewBinding(key.WithKeys("y"), key.WithHelp("y", "copy ssh command for article")),
+		run: (*model).copyShareCommand,
+	},
+	{
+		key: key.NewBinding(key.WithKeys("s"), key.WithHelp("s", "save article to disk")),
+		run: (*model).clipArticle,
+	},
+	{
+		key: key.NewBinding(key.WithKeys("Q"), key.WithHelp("Q", "change how q quits")),
+		run: (*model).cycleQuitMode,
+	},
+	{
+		key:   key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "show raw XML")),
+		run:   (*model).showRawXML,
+		debug: true,
+	},
+	{
+		key:     key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "toggle help")),
+		run:     func(m *model) tea.Cmd { return m.sendListKey('?') },
+		listKey: true,
+	},
+	{
+		key:     key.NewBinding(key.WithKeys("q"), key.WithHelp("q", "quit")),
+		run:     (*model).quit,
+		listKey: true,
+	},
+}
+
+func shortHelp() []key.Binding {
+	var b []key.Binding
+	for _, a := range actions {
+		if a.short && !a.listKey && a.available() {
+			b = append(b, a.key)
+		}
+	}
+	return b
+}
+
+func fullHelp() []key.Binding {
+	var b []key.Binding
+	for _, a := range actions {
+		if !a.listKey && a.available() {
+			b = append(b, a.key)
+		}
+	}
+	return b
+}
+
+// Quit modes, cycled with Q. ctrl+c always quits straight away.
+const (
+	quitImmediately = ""
+	quitConfirm     = "confirm"
+	quitCtrlCOnly   = "ctrl+c"
+)
+
+var quitModes = []string{quitImmediately, quitConfirm, quitCtrlCOnly}
+
+var quitModeLabels = map[string]string{
+	quitImmediately: "q quits",
+	quitConfirm:     "q asks before quitting",
+	quitCtrlCOnly:   "only ctrl+c quits",
+}
+
+func nextQuitMode(mode string) string {
+	for i, m := range quitModes {
+		if m == mode {
+			return quitModes[(i+1)%len(quitModes)]
+		}
+	}
+	return quitImmediately
+}
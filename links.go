@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// neverSafeSchemes can run code or smuggle content in when opened, so
+// -link-schemes refuses to allow them.
+var neverSafeSchemes = []string{"javascript", "vbscript", "data", "file"}
+
+// schemeList is the -link-schemes flag, a comma-separated list that
+// replaces the default rather than adding to it.
+type schemeList []string
+
+func (l schemeList) String() string { return strings.Join(l, ",") }
+
+func (l *schemeList) Set(s string) error {
+	var schemes schemeList
+	for _, f := range strings.Split(s, ",") {
+		f = strings.ToLower(strings.TrimSpace(f))
+		if f == "" {
+			continue
+		}
+		if slices.Contains(neverSafeSchemes, f) {
+			return fmt.Errorf("%s links can't be allowed", f)
+		}
+		schemes = append(schemes, f)
+	}
+	*l = schemes
+	return nil
+}
+
+// safeLink returns link if it parses and has one of the -link-schemes,
+// and "" otherwise. Every link taken from a feed goes through it before
+// it is shown, copied or opened.
+func safeLink(link string) string {
+	link = strings.TrimSpace(link)
+	u, err := url.Parse(link)
+	if err != nil || !slices.Contains(cfg.linkSchemes, strings.ToLower(u.Scheme)) {
+		return ""
+	}
+	return link
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yuin/goldmark"
+	gast "github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+)
+
+func TestSafeLink(t *testing.T) {
+	tests := []struct {
+		link string
+		safe bool
+	}{
+		{"https://example.com/a", true},
+		{"http://example.com/a", true},
+		{"mailto:tips@example.com", true},
+		{"  https://example.com/padded  ", true},
+		{"javascript:alert(1)", false},
+		{"JaVaScRiPt:alert(1)", false},
+		{"data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==", false},
+		{"vbscript:msgbox(1)", false},
+		{"java\x00script:alert(1)", false},
+		{"file:///etc/passwd", false},
+		{"//example.com/no-scheme", false},
+		{"/relative", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := safeLink(tt.link); (got != "") != tt.safe {
+			t.Errorf("safeLink(%q) = %q, want safe = %v", tt.link, got, tt.safe)
+		}
+	}
+}
+
+func TestLinkSchemesFlag(t *testing.T) {
+	var l schemeList
+	if err := l.Set("HTTPS, gemini"); err != nil || l.String() != "https,gemini" {
+		t.Errorf("got %q, %v", l, err)
+	}
+	for _, bad := range []string{"https,javascript", "data"} {
+		if err := l.Set(bad); err == nil {
+			t.Errorf("%q: got nil error", bad)
+		}
+	}
+}
+
+func TestMaliciousFeedLinks(t *testing.T) {
+	c := cfg
+	c.sanitize = perFeed{"": "ugc"}
+	withConfig(t, c)
+
+	feed := testFeed()
+	feed.Link = "javascript:alert('home')"
+	feed.Items[0].Link = "javascript:alert('source')"
+	feed.Items[0].Description = `<a href="javascript:alert(1)">click</a> <img src="data:image/png;base64,AAAA" alt="pic">`
+	feed.Items[0].Comments = []xmlText{{Value: "data:text/html,<script>alert(1)</script>"}}
+	feed.Items[0].Enclosures = []enclosure{{URL: "javascript:alert('media')", Type: "audio/mpeg"}}
+	m := newModel(feed, userState{}, 80, 24)
+	if m.homepage != "" {
+		t.Errorf("homepage = %q", m.homepage)
+	}
+	item := m.list.Items()[0].(rssListItem)
+	if item.link != "" || item.commentsLink != "" || len(item.media()) != 0 {
+		t.Errorf("item kept a dangerous link: %q, %q, %v", item.link, item.commentsLink, item.media())
+	}
+	m.selected = item
+	for _, bad := range []string{"javascript:", "data:"} {
+		if md := m.detailMarkdown(); strings.Contains(md, bad) {
+			t.Errorf("detail shows a %s link: %q", bad, md)
+		}
+	}
+	// Even if a policy let them through, htmlToMarkdown drops them.
+	md := htmlToMarkdown(`<a href="javascript:alert(1)">x</a><img src="data:," alt="y">`)
+	if strings.Contains(md, "javascript:") || strings.Contains(md, "data:") {
+		t.Errorf("htmlToMarkdown kept a dangerous link: %q", md)
+	}
+
+	// Text that is markdown already must not become a link either.
+	injected := []string{
+		"Read [this](javascript:alert(1))",
+		"&lt;javascript:alert(1)&gt;",
+		"<code>`</code>[x](javascript:alert(1))<code>`</code>",
+		"<pre>```\n[x](javascript:alert(1))\n```</pre>",
+		`<a href="https://example.com/a)[x](javascript:alert(1)">t</a>`,
+	}
+	for _, desc := range injected {
+		item.desc = desc
+		item.title = "[Title](javascript:alert(1))"
+		m.selected = item
+		if dest := markdownLinks(t, m.detailMarkdown()); slices.ContainsFunc(dest, func(d string) bool {
+			return d != "" && !strings.HasPrefix(d, "https://")
+		}) {
+			t.Errorf("%q became links to %q", desc, dest)
+		}
+	}
+}
+
+// markdownLinks parses md as glamour does and returns where its links and
+// images point.
+func markdownLinks(t *testing.T, md string) []string {
+	t.Helper()
+	src := []byte(md)
+	var dest []string
+	doc := goldmark.New(goldmark.WithExtensions(extension.GFM)).Parser().Parse(text.NewReader(src))
+	err := gast.Walk(doc, func(n gast.Node, entering bool) (gast.WalkStatus, error) {
+		if !entering {
+			return gast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *gast.Link:
+			dest = append(dest, string(n.Destination))
+		case *gast.Image:
+			dest = append(dest, string(n.Destination))
+		case *gast.AutoLink:
+			dest = append(dest, string(n.URL(src)))
+		}
+		return gast.WalkContinue, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dest
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/ssh"
+)
+
+// liteEnv, sent with e.g. ssh -o SetEnv=NEWS_LITE=1, starts a session in
+// lite mode.
+const liteEnv = "NEWS_LITE"
+
+// liteFPS caps redraws for sessions that start in lite mode, against
+// bubbletea's default of 60. The program's frame rate is fixed once it
+// starts, so toggling lite mode later leaves it as it was.
+const liteFPS = 10
+
+// wantsLite reports whether the client asked for lite mode.
+func wantsLite(s ssh.Session) bool {
+	for _, kv := range s.Environ() {
+		if v, ok := strings.CutPrefix(kv, liteEnv+"="); ok {
+			return v != "" && v != "0"
+		}
+	}
+	return false
+}
+
+// toggleLite switches lite mode, which skips images, link previews and the
+// -dwell countdown for users on slow or metered links.
+func (m *model) toggleLite() tea.Cmd {
+	m.lite = !m.lite
+	m.updateTitle()
+	if m.lite {
+		// Orphan any dwell tick in flight.
+		m.dwellSeq++
+		m.dwellLeft = 0
+		return m.list.NewStatusMessage("Lite mode on: no images, previews or auto-advance")
+	}
+	cmd := m.list.NewStatusMessage("Lite mode off")
+	if m.showDetail {
+		return tea.Batch(cmd, m.startDwell(), m.fetchPreview(m.selected.link))
+	}
+	return cmd
+}
+
+// markdownImage matches an image as htmlToMarkdown writes it: its alt
+// text may hold escaped brackets, and linkDest has encoded any parentheses
+// in its source.
+var markdownImage = regexp.MustCompile(`!\[((?:\\.|[^\]\\])*)\]\([^)]*\)`)
+
+// dropImages replaces the images htmlToMarkdown emits with their alt text.
+func dropImages(md string) string {
+	return markdownImage.ReplaceAllString(md, "$1")
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestLiteMode(t *testing.T) {
+	c := cfg
+	c.dwell = time.Minute
+	c.linkPreview = true
+	c.sanitize = perFeed{"": "ugc"}
+	withConfig(t, c)
+
+	feed := testFeed()
+	feed.Items[0].Description = `<p>Vote tally <img src="https://example.com/tally.png" alt="the tally">` +
+		`<img src="https://example.com/map.png" alt="map [2025]"></p>`
+	var m tea.Model = newModel(feed, userState{}, 80, 40)
+	m, _ = m.Update(keyMsg("L"))
+	if !strings.Contains(m.(model).list.Title, "lite mode") {
+		t.Errorf("title %q doesn't show lite mode", m.(model).list.Title)
+	}
+	m, _ = m.Update(keyMsg("enter"))
+	lm := m.(model)
+	if lm.dwellLeft != 0 {
+		t.Error("started the dwell countdown in lite mode")
+	}
+	if len(lm.previews) != 0 {
+		t.Error("fetched a link preview in lite mode")
+	}
+	if s := lm.dwellStatus(); s != "" {
+		t.Errorf("dwell status = %q, want none", s)
+	}
+	md := lm.detailMarkdown()
+	if strings.Contains(md, ".png") || !strings.Contains(md, "the tally") || !strings.Contains(md, `map \[2025\]`) {
+		t.Errorf("image not replaced by its alt text: %q", md)
+	}
+
+	m, _ = m.Update(keyMsg("L"))
+	if lm = m.(model); lm.dwellStatus() == "" || len(lm.previews) != 1 {
+		t.Error("leaving lite mode in the detail view didn't resume the countdown and preview")
+	}
+}
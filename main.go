@@ -2,21 +2,30 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/xml"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log/slog"
 	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
 	"os/signal"
 	"runtime"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
+	"unicode"
 
+	"github.com/aymanbagabas/go-osc52/v2"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
@@ -26,36 +35,164 @@ import (
 	"github.com/charmbracelet/wish/activeterm"
 	"github.com/charmbracelet/wish/bubbletea"
 	"github.com/charmbracelet/wish/logging"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/muesli/termenv"
+	gossh "golang.org/x/crypto/ssh"
 )
 
 const (
-	host    = "0.0.0.0"
-	port    = "22"
-	feedURL = "https://rss.politico.com/playbook.xml"
+	host           = "0.0.0.0"
+	port           = "22"
+	defaultFeedURL = "https://rss.politico.com/playbook.xml"
 )
 
+// states persists per-user preferences; nil when persistence is off.
+var states *stateStore
+
+// feeds is the feed shared by all sessions.
+var feeds *feedCache
+
+// feedMsg delivers a feed fetched for a session that started without one.
+type feedMsg struct {
+	feed RSSFeed
+	err  error
+	// fallback is set when feed is the -fallback-feed.
+	fallback bool
+	edits    map[string]RSSItem
+}
+
+func loadFeed() tea.Msg {
+	if feeds == nil {
+		feed, err := scrapeUrlFeed(cfg.feedURL)
+		return feedMsg{feed: feed, err: err}
+	}
+	feed, err := feeds.get()
+	return feedMsg{feed: feed, err: err, fallback: feeds.onFallback(), edits: feeds.recentEdits()}
+}
+
 func teaHandler(s ssh.Session) (tea.Model, []tea.ProgramOption) {
 	pty, _, _ := s.Pty()
-	feed, err := scrapeUrlFeed(feedURL)
+	user := userKey(s.PublicKey())
+	member := isMember(s)
+	// Guests start afresh and leave nothing behind.
+	store := states
+	if !member {
+		store = nil
+	}
+	st, err := store.load(user)
 	if err != nil {
-		log.Error("Failed to fetch feed", "error", err)
-		return model{}, []tea.ProgramOption{tea.WithAltScreen()}
+		log.Error("Failed to load user state", "error", err)
+	}
+	// A warm cache is shown straight away; otherwise Init fetches it.
+	feed, cached := feeds.peek()
+	m := newModel(feed, st, pty.Window.Width, pty.Window.Height)
+	m.loading = !cached
+	m.setEdits(feeds.recentEdits())
+	if isMonochrome(s) {
+		m.monochrome = true
+		m.applyDelegate()
+	}
+	m.feedURL = cfg.feedURL
+	m.motd = motd.get()
+	m.setFallback(feeds.onFallback())
+	m.out = s
+	m.store, m.user, m.member = store, user, member
+	m.canClip = user != "" && cfg.clipUsers[user]
+	// Users who hid their name are never asked their agent for it; they
+	// are greeted anonymously if they show it again this session.
+	if cfg.agentNames && !st.HideName {
+		m.displayName = displayName(agentKeyComment(s))
+	} else {
+		m.displayName = anonymousName
+	}
+	m.lite = wantsLite(s)
+	m.holdSplash()
+	m.updateTitle()
+	m.deepLink = strings.Join(s.Command(), " ")
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	if m.lite {
+		opts = append(opts, tea.WithFPS(liteFPS))
+	}
+	if cfg.pauseOnBlur {
+		opts = append(opts, tea.WithReportFocus())
+	}
+	return m, opts
+}
+
+// isMonochrome reports whether the client asked for no colors or can't show any.
+func isMonochrome(s ssh.Session) bool {
+	for _, kv := range s.Environ() {
+		if strings.HasPrefix(kv, "NO_COLOR=") && kv != "NO_COLOR=" {
+			return true
+		}
 	}
-	delegate := list.NewDefaultDelegate()
-	delegate.ShowDescription = true
-	m := model{list: list.New(toListItems(feed.Items), delegate, pty.Window.Width, pty.Window.Height)}
-	m.list.Title = feed.Title
-	return m, []tea.ProgramOption{tea.WithAltScreen()}
+	return bubbletea.MakeRenderer(s).ColorProfile() == termenv.Ascii
 }
 
 func main() {
+	cfg.registerFlags(flag.CommandLine)
+	flag.Parse()
+	if err := cfg.validate(); err != nil {
+		log.Fatal("Invalid configuration", "error", err)
+	}
+	if cfg.auditLog != "" {
+		var err error
+		if audit, err = newAuditLog(cfg.auditLog, cfg.auditMaxSize<<20); err != nil {
+			log.Fatal("Could not open audit log", "error", err)
+		}
+		defer audit.Close()
+	}
+	fetchLimit = newTokenBucket(cfg.fetchRate, cfg.fetchBurst)
+	openHook = newHookRunner(cfg.openHookURL, cfg.openHookExec, cfg.openHookTimeout)
+	feeds = newFeedCache(cfg.feedURL, cfg.cacheTTL)
+	feeds.fallbackURL = cfg.fallbackFeed
+	// Without an explicit -cache-ttl, refresh as often as the feed asks.
+	feeds.followFeedTTL = true
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "cache-ttl" {
+			feeds.followFeedTTL = false
+		}
+	})
+	if cfg.fetchMode == fetchEager {
+		if _, err := feeds.get(); err != nil {
+			log.Error("Failed to warm feed cache", "error", err)
+		}
+	}
+	refreshCtx, stopRefresh := context.WithCancel(context.Background())
+	defer stopRefresh()
+	go feeds.run(refreshCtx)
+	motd = newMOTDCache(cfg.motdURL, cfg.motd)
+	go motd.run(refreshCtx, cfg.motdRefresh)
+	if cfg.versionURL != "" {
+		go watchVersion(refreshCtx, cfg.versionURL, cfg.versionCheck)
+	}
+	if cfg.stateDir != "" {
+		var err error
+		if states, err = newStateStore(cfg.stateDir); err != nil {
+			log.Error("User state will not be saved", "error", err)
+		}
+	}
+
+	hostKeys := wish.WithHostKeyPath(".ssh/id_ed25519")
+	if cfg.hostKeyDir != "" {
+		signers, err := loadHostKeys(cfg.hostKeyDir, cfg.primaryHostKey)
+		if err != nil {
+			log.Fatal("Could not load host keys", "error", err)
+		}
+		hostKeys = withHostKeys(signers)
+	}
 	s, err := wish.NewServer(
 		wish.WithAddress(net.JoinHostPort(host, port)),
-		wish.WithHostKeyPath(".ssh/id_ed25519"),
+		hostKeys,
+		// Accept everyone: the key only identifies returning members, and
+		// keyboard-interactive lets clients without a key in as guests.
+		wish.WithPublicKeyAuth(func(ssh.Context, ssh.PublicKey) bool { return true }),
+		wish.WithKeyboardInteractiveAuth(func(ssh.Context, gossh.KeyboardInteractiveChallenge) bool { return true }),
 		wish.WithMiddleware(
 			bubbletea.Middleware(teaHandler),
 			activeterm.Middleware(),
 			logging.Middleware(),
+			auditMiddleware(),
 		),
 	)
 	if err != nil {
@@ -64,7 +201,7 @@ func main() {
 
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
-	log.Info("Starting SSH server", "host", host, "port", port)
+	log.Info("Starting SSH server", "host", host, "port", port, "version", version)
 	go func() {
 		if err = s.ListenAndServe(); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
 			log.Error("Could not start server", "error", err)
@@ -79,88 +216,643 @@ func main() {
 	if err := s.Shutdown(ctx); err != nil && !errors.Is(err, ssh.ErrServerClosed) {
 		log.Error("Could not stop server", "error", err)
 	}
+	// Sessions Shutdown gave up waiting for are still open; save what
+	// they, and any that just closed, have changed.
+	if err := states.flush(); err != nil {
+		log.Error("Failed to save user state", "error", err)
+	}
 }
 
 // model and list helpers
 
 type rssListItem struct {
-	title string
-	desc  string
-	link  string
+	title  string
+	desc   string
+	link   string
+	author string
+	// comments is the comment count badge text, empty when the feed has none.
+	comments     string
+	commentsLink string
+	published    time.Time
+	scope        searchScope
+	rss          RSSItem
+	// dupIndex and dupCount are set when several items share a title, so
+	// the list can tell them apart (e.g. updates to the same story).
+	dupIndex int
+	dupCount int
+	// edited is set when the item changed since it was first seen.
+	edited bool
+	// dateWarning is set when published looks wrong; see dateWarning.
+	dateWarning string
+	// descRepeatsTitle is set when desc says no more than the title.
+	descRepeatsTitle bool
 }
 
-func (r rssListItem) Title() string       { return r.title }
-func (r rssListItem) Description() string { return r.desc }
-func (r rssListItem) FilterValue() string { return r.title }
+func (r rssListItem) Title() string {
+	title := r.title
+	if r.dupCount > 1 {
+		title = fmt.Sprintf("%s (%d/%d)", title, r.dupIndex, r.dupCount)
+	}
+	if r.comments != "" {
+		title += " 💬 " + r.comments
+	}
+	if r.edited {
+		title += " ✎"
+	}
+	if r.dateWarning != "" {
+		title += " ⚠"
+	}
+	return title
+}
+
+// Description is blank for items whose description only repeats the
+// title. The row keeps its height, as every row in the list must.
+func (r rssListItem) Description() string {
+	if r.descRepeatsTitle && cfg.hideTitleDescriptions {
+		return ""
+	}
+	return r.desc
+}
+func (r rssListItem) FilterValue() string { return r.scopeValue() }
 
 type model struct {
 	list       list.Model
 	showDetail bool
 	selected   rssListItem
+	homepage   string
+	feedURL    string
+	feedTitle  string
+	feedTTL    time.Duration
+	// items is the feed as listed, before any day headers go in.
+	items []RSSItem
+	// fallback is set while showing the -fallback-feed.
+	fallback bool
+	// motd is the message of the day as of the session's start.
+	motd string
+	// displayName greets the user; see agentKeyComment.
+	displayName string
+	monochrome  bool
+	peek        bool
+	searchScope searchScope
+	user        string
+	state       userState
+	store       *stateStore
+	// confirmingQuit is set after q in quitConfirm mode; the next key
+	// either confirms or cancels.
+	confirmingQuit bool
+	showPalette    bool
+	palette        palette
+	showInfo       bool
+	// edits holds the previous version of items that changed; showDiff
+	// shows diffBody, the changes to one of them.
+	edits    map[string]RSSItem
+	showDiff bool
+	diffBody string
+	// focusMode shows only the selected article, without the list or any
+	// chrome.
+	focusMode     bool
+	width, height int
+	// loading is set until the first feed arrives; loadErr if it didn't.
+	loading bool
+	loadErr error
+	// showRaw shows rawItem's XML instead of the detail modal (-debug only).
+	showRaw bool
+	rawItem RSSItem
+	// quickLook shows the item under the cursor in a popover instead of
+	// the detail modal; see toggleQuickLook.
+	quickLook bool
+	// dwellLeft counts down to auto-advancing the detail view (-dwell);
+	// dwellSeq tells current ticks from stale ones.
+	dwellLeft   time.Duration
+	dwellPaused bool
+	dwellSeq    int
+	// blurred is set while the client reports its terminal out of focus.
+	blurred bool
+	// authorFilter, if set, narrows the list to one author's articles;
+	// see sessionFilters.
+	authorFilter string
+	// showSplash holds the splash after loading; see holdSplash.
+	showSplash bool
+	// lite skips images, link previews and timers; see toggleLite.
+	lite bool
+	// deepLink is the key of the item to open once the feed is in, from
+	// a shareCommand.
+	deepLink string
+	// canClip lets the session save articles to -clip-dir.
+	canClip bool
+	// member is unset for guests, whose state is never saved; see
+	// isMember.
+	member     bool
+	detailMode detailMode
+	// renders caches glamour output by its markdown; see render.
+	renders map[string]string
+	// focus holds focus mode's renderer; see focusView.
+	focus *focusRenderer
+	// previews caches link previews (-link-preview) by link for the session.
+	previews map[string]linkPreview
+	// out is the client's terminal, used to emit escape sequences (OSC 52)
+	// that bubbletea has no command for.
+	out io.Writer
+}
+
+func newModel(feed RSSFeed, st userState, width, height int) model {
+	m := model{state: st, renders: map[string]string{}, focus: &focusRenderer{}}
+	m.list = list.New(nil, newItemDelegate(&m), width, height)
+	m.list.AdditionalShortHelpKeys = shortHelp
+	m.list.AdditionalFullHelpKeys = fullHelp
+	m.width, m.height = width, height
+	m.setFeed(feed)
+	return m
+}
+
+// setFeed replaces the list contents with feed.
+func (m *model) setFeed(feed RSSFeed) {
+	m.feedTitle = feed.Title
+	m.updateTitle()
+	m.homepage = safeLink(feed.Link)
+	m.feedTTL = feed.ttl()
+	m.items = sortItems(dedupItems(feed.Items), cfg.sortKeys)
+	m.setItems()
+}
+
+// setItems rebuilds the list from m.items; see pipeline.go for the order
+// things are filtered in.
+func (m *model) setItems() {
+	items := toListItems(m.filteredItems())
+	if m.state.GroupByDay {
+		items = groupByDay(items, time.Now())
+	}
+	m.setListItems(items)
+	// The window may have been resized while the feed was loading; size
+	// the list, and so its pages, to how it is now.
+	m.resize()
+	m.setEdits(m.edits)
+	m.skipHeader(0)
+	m.setSearchScope(m.searchScope)
 }
 
 func (m model) Init() tea.Cmd {
-	return nil
+	splash := m.splashTimer()
+	if m.loading {
+		return tea.Batch(loadFeed, splash)
+	}
+	if m.deepLink != "" {
+		return tea.Batch(func() tea.Msg { return deepLinkMsg{} }, splash)
+	}
+	return splash
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
-			return m, tea.Quit
-		case "enter":
-			if item, ok := m.list.SelectedItem().(rssListItem); ok {
-				m.showDetail = true
-				m.selected = item
+		if m.showSplash && !m.loading && msg.String() != "ctrl+c" {
+			m.showSplash = false
+			return m, nil
+		}
+		if m.showPalette {
+			return m, m.updatePalette(msg)
+		}
+		// While typing a filter every key belongs to the filter input,
+		// bar tab which changes what it searches.
+		if m.list.SettingFilter() {
+			if msg.String() == "tab" {
+				m.setSearchScope(m.searchScope.next())
+				return m, nil
+			}
+			break
+		}
+		if m.confirmingQuit {
+			m.confirmingQuit = false
+			switch msg.String() {
+			case "ctrl+c", "q", "y":
+				return m, tea.Quit
 			}
 			return m, nil
+		}
+		switch msg.String() {
+		case "ctrl+c":
+			return m, tea.Quit
+		case "q":
+			cmd := m.quit()
+			return m, cmd
+		case ":":
+			m.showPalette = true
+			m.palette = newPalette()
+			return m, textinput.Blink
 		case "esc":
+			if m.focusMode {
+				m.focusMode = false
+				return m, nil
+			}
+			if !m.showDetail && !m.showRaw && !m.showInfo && !m.showDiff && !m.quickLook &&
+				m.list.FilterState() == list.FilterApplied {
+				// Nothing to close, so esc goes to the list to clear
+				// the search.
+				break
+			}
 			m.showDetail = false
+			m.quickLook = false
+			m.showRaw = false
+			m.showInfo = false
+			m.showDiff = false
 			return m, nil
-		case "o":
-			if m.showDetail {
-				go openBrowser(m.selected.link)
+		}
+		for _, a := range actions {
+			if a.listKey || !a.available() || (a.detailOnly && !m.showDetail) {
+				continue
+			}
+			if key.Matches(msg, a.key) {
+				cmd := a.run(&m)
+				return m, cmd
 			}
+		}
+	case feedMsg:
+		m.loading = false
+		if msg.err != nil {
+			log.Error("Failed to fetch feed", "error", msg.err)
+			m.loadErr = msg.err
 			return m, nil
 		}
+		m.setFallback(msg.fallback)
+		m.setFeed(msg.feed)
+		m.setEdits(msg.edits)
+		return m, m.openDeepLink()
+	case splashDoneMsg:
+		m.showSplash = false
+		return m, nil
+	case deepLinkMsg:
+		return m, m.openDeepLink()
+	case dwellTickMsg:
+		return m, m.updateDwell(msg)
+	case previewMsg:
+		m.previews[msg.link] = msg.preview
+		return m, nil
+	case tea.BlurMsg:
+		return m, m.setBlurred(true)
+	case tea.FocusMsg:
+		return m, m.setBlurred(false)
 	case tea.WindowSizeMsg:
-		h, v := docStyle.GetFrameSize()
-		m.list.SetSize(msg.Width-h, msg.Height-v)
+		m.width, m.height = msg.Width, msg.Height
+		m.resize()
 	}
 
 	var cmd tea.Cmd
+	from := m.list.Index()
 	m.list, cmd = m.list.Update(msg)
+	m.skipHeader(from)
 	return m, cmd
 }
 
+// quit applies the user's quit mode to a bare q.
+func (m *model) quit() tea.Cmd {
+	switch m.state.QuitMode {
+	case quitConfirm:
+		m.confirmingQuit = true
+		return nil
+	case quitCtrlCOnly:
+		return m.list.NewStatusMessage("Press ctrl+c to quit")
+	}
+	return tea.Quit
+}
+
+func (m *model) cycleQuitMode() tea.Cmd {
+	m.state.QuitMode = nextQuitMode(m.state.QuitMode)
+	return tea.Batch(m.saveState(), m.list.NewStatusMessage(quitModeLabels[m.state.QuitMode]))
+}
+
+func (m *model) toggleAuthor() tea.Cmd {
+	m.state.HideAuthor = !m.state.HideAuthor
+	m.applyDelegate()
+	return m.saveState()
+}
+
+func (m *model) toggleAgeTint() tea.Cmd {
+	m.state.NoAgeTint = !m.state.NoAgeTint
+	m.applyDelegate()
+	return m.saveState()
+}
+
+// sendListKey feeds the list a key press, for actions that the list
+// implements itself.
+func (m *model) sendListKey(r rune) tea.Cmd {
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	return cmd
+}
+
+// current is the article actions apply to: the one open in the detail
+// view, or else the one under the cursor. ok is false when there is none,
+// say because the feed or the filter left the list empty.
+func (m *model) current() (rssListItem, bool) {
+	if m.showDetail {
+		return m.selected, true
+	}
+	item, ok := m.list.SelectedItem().(rssListItem)
+	return item, ok
+}
+
+// noSelection tells the user an action had nothing to act on.
+func (m *model) noSelection() tea.Cmd {
+	return m.list.NewStatusMessage("No article selected")
+}
+
+// noArticleOpen tells the user a detail view action, run from the
+// palette, needs an article open first.
+func (m *model) noArticleOpen() tea.Cmd {
+	return m.list.NewStatusMessage("Open an article first")
+}
+
+// openSelected opens the article under the cursor in the detail view.
+func (m *model) openSelected() tea.Cmd {
+	item, ok := m.list.SelectedItem().(rssListItem)
+	if !ok {
+		return m.noSelection()
+	}
+	return m.openArticle(item)
+}
+
+// openArticle shows item in the detail view and records it as read.
+func (m *model) openArticle(item rssListItem) tea.Cmd {
+	m.showDetail, m.quickLook = true, false
+	m.selected = item
+	return tea.Batch(m.recordRead(item, time.Now()), m.startDwell(), m.fetchPreview(item.link))
+}
+
+// openLink opens the article in the detail view in the browser.
+func (m *model) openLink() tea.Cmd {
+	if !m.showDetail {
+		return m.noArticleOpen()
+	}
+	if m.selected.link == "" {
+		return m.list.NewStatusMessage("This article has no link to open")
+	}
+	go openBrowser(m.selected.link)
+	return nil
+}
+
 func (m model) View() string {
+	switch {
+	case m.loading:
+		return m.splashView()
+	case m.loadErr != nil:
+		return docStyle.Render(fmt.Sprintf("Couldn't load the feed: %v\n\nPress q to quit.", m.loadErr))
+	case m.showSplash:
+		return m.splashView()
+	case m.focusMode && m.showDetail:
+		return m.focusView()
+	case m.showPalette:
+		return m.mainView() + "\n\n" + modalStyle.Render(m.palette.View())
+	case m.confirmingQuit:
+		return m.mainView() + "\n" + promptStyle.Render("Press q again or y to quit, any other key to stay.")
+	}
+	return m.mainView()
+}
+
+func (m model) mainView() string {
 	listView := docStyle.Render(m.list.View())
+	top := docStyle.GetMarginTop() + docStyle.GetPaddingTop()
+	if b := m.banner(); b != "" {
+		listView = b + "\n" + listView
+		top += lipgloss.Height(b)
+	}
+	if m.quickLook && !m.showDetail {
+		listView = m.withQuickLook(listView, top)
+	}
+	listView += "\n" + m.footer()
+	if m.showDiff {
+		return listView + "\n\n" + modalStyle.Render(m.diffView())
+	}
+	if m.showInfo {
+		out, err := glamour.Render(m.infoMarkdown(), "dark")
+		if err != nil {
+			slog.Default().Error("Failed to render markdown", "error", err)
+			return listView
+		}
+		return listView + "\n\n" + modalStyle.Render(out)
+	}
+	if m.showRaw {
+		out, err := glamour.Render(rawItemMarkdown(m.rawItem), "dark")
+		if err != nil {
+			slog.Default().Error("Failed to render markdown", "error", err)
+			return listView
+		}
+		return listView + "\n\n" + modalStyle.Render(out)
+	}
 	if m.showDetail {
-		out, err := glamour.Render(
-			fmt.Sprintf("# %s\n\n%s\n\n[Source](%s)\n\n*Press 'o' to open in browser, press Esc to go back.*",
-				m.selected.title,
-				m.selected.desc,
-				m.selected.link,
-			), "dark")
+		body, err := m.detailBody()
+		if err != nil {
+			slog.Default().Error("Failed to render markdown", "error", err)
+			return listView
+		}
+		hint, err := glamour.Render(m.detailHint(), "dark")
 		if err != nil {
 			slog.Default().Error("Failed to render markdown", "error", err)
 			return listView
 		}
-		modal := modalStyle.Render(out)
+		if m.detailMode != detailRendered {
+			// Line the text up with glamour's margin around the hint.
+			body = plainDetailStyle.Render(body) + "\n"
+		}
+		modal := modalStyle.Render(body + hint)
 		return listView + "\n\n" + modal
 	}
 	return listView
 }
 
+// banner is the notices above the list, or "" for none.
+func (m model) banner() string {
+	// Cut lines to the window so the banner is as tall as resize thinks.
+	fit := func(s lipgloss.Style) lipgloss.Style {
+		if m.width > 0 {
+			return s.MaxWidth(m.width)
+		}
+		return s
+	}
+	var lines []string
+	if m.fallback {
+		lines = append(lines, fit(bannerStyle).Render("The usual feed is unavailable; showing a backup until it's back."))
+	}
+	if m.motd != "" {
+		lines = append(lines, fit(motdStyle).Render(m.motd))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// footer is the line under the list: any filters, then the session's
+// mode. It is cut to the window so resize can count it as one line.
+func (m model) footer() string {
+	line := m.modeLabel()
+	if f := m.filterLabel(); f != "" {
+		line = f + " · " + line
+	}
+	if m.width > 0 {
+		line = ansi.Truncate(line, m.width-footerStyle.GetHorizontalFrameSize(), "…")
+	}
+	return footerStyle.Render(line)
+}
+
+// resize fits the list to the window, less the banner above it.
+func (m *model) resize() {
+	h, v := docStyle.GetFrameSize()
+	v++ // footer
+	if b := m.banner(); b != "" {
+		v += lipgloss.Height(b)
+	}
+	m.list.SetSize(m.width-h, m.height-v)
+}
+
+// setFallback records whether the feed shown is the fallback, whose own
+// per-feed settings then apply.
+func (m *model) setFallback(on bool) {
+	m.fallback = on
+	if on {
+		m.feedURL = cfg.fallbackFeed
+	}
+	m.resize()
+}
+
+// detailMarkdown is the selected article without any UI hints.
+func (m model) detailMarkdown() string {
+	links := fmt.Sprintf("[Source](%s)", linkDest(m.selected.link))
+	if m.selected.commentsLink != "" {
+		links += fmt.Sprintf(" · [Comments](%s)", linkDest(m.selected.commentsLink))
+	}
+	for _, e := range m.selected.media() {
+		links += fmt.Sprintf(" · [%s](%s)", escapeMarkdown(e.label()), linkDest(e.URL))
+	}
+	desc := sanitizeDescription(m.feedURL, m.selected.desc)
+	if m.lite {
+		desc = dropImages(desc)
+	}
+	title := escapeMarkdown(strings.Join(strings.Fields(m.selected.title), " "))
+	md := fmt.Sprintf("# %s\n\n%s\n\n%s", title, desc, links)
+	if w := m.selected.dateWarning; w != "" {
+		md = fmt.Sprintf("# %s\n\n*⚠ %s*\n\n%s\n\n%s", title, w, desc, links)
+	}
+	if p := m.previewMarkdown(); p != "" && !m.lite {
+		md += "\n\n" + p
+	}
+	return md
+}
+
+// updateTitle shows the feed title, greeting the user unless they opted out.
+func (m *model) updateTitle() {
+	m.list.Title = m.feedTitle
+	if m.peek {
+		m.list.Title += " · headlines only"
+	}
+	if m.lite {
+		m.list.Title += " · lite mode"
+	}
+	if m.displayName != "" && !m.state.HideName {
+		m.list.Title += " · Welcome, " + m.displayName
+	}
+}
+
+func (m *model) toggleName() tea.Cmd {
+	m.state.HideName = !m.state.HideName
+	m.updateTitle()
+	return m.saveState()
+}
+
+// applyDelegate rebuilds the list delegate after a display setting changes.
+func (m *model) applyDelegate() {
+	m.list.SetDelegate(newItemDelegate(m))
+}
+
+// togglePeek flips the session between the normal list and a dense
+// headlines-only one for quick scanning. Unlike the other display settings
+// it isn't saved.
+func (m *model) togglePeek() tea.Cmd {
+	m.peek = !m.peek
+	m.applyDelegate()
+	m.updateTitle()
+	return nil
+}
+
+// saveState persists the user's preferences off the update loop. They are
+// queued straight away, so a shutdown flushes them even if the command
+// never gets to run.
+func (m model) saveState() tea.Cmd {
+	store, user := m.store, m.user
+	store.queue(user, m.state)
+	return func() tea.Msg {
+		if err := store.write(user); err != nil {
+			log.Error("Failed to save user state", "error", err)
+		}
+		return nil
+	}
+}
+
+// visitHomepage copies the feed's homepage to the client's clipboard over
+// OSC 52 and shows it as an OSC 8 hyperlink, since a browser can't be opened
+// on the other end of an SSH session.
+func (m *model) visitHomepage() tea.Cmd {
+	if m.homepage == "" {
+		return m.list.NewStatusMessage("This feed doesn't list a homepage")
+	}
+	if m.out != nil {
+		if _, err := osc52.New(m.homepage).WriteTo(m.out); err != nil {
+			log.Error("Failed to copy homepage", "error", err)
+		}
+	}
+	link := ansi.SetHyperlink(m.homepage) + m.homepage + ansi.ResetHyperlink()
+	return m.list.NewStatusMessage("Copied homepage: " + link)
+}
+
 func toListItems(items []RSSItem) []list.Item {
+	titles := make(map[string]int, len(items))
+	for _, item := range items {
+		titles[item.Title]++
+	}
+	seen := make(map[string]int, len(items))
 	l := make([]list.Item, len(items))
+	now := time.Now()
 	for i, item := range items {
-		l[i] = rssListItem{title: item.Title, desc: item.Description, link: item.Link}
+		seen[item.Title]++
+		li := rssListItem{
+			title:        item.Title,
+			desc:         item.Description,
+			link:         safeLink(item.permalink()),
+			author:       item.Creator,
+			commentsLink: safeLink(item.commentsLink()),
+			rss:          item,
+			published:    parsePubDate(item.PublishDate),
+			dupIndex:     seen[item.Title],
+			dupCount:     titles[item.Title],
+		}
+		li.descRepeatsTitle = repeatsTitle(item.Title, item.Description)
+		li.dateWarning = dateWarning(li.published, now)
+		if n, ok := item.commentCount(); ok {
+			li.comments = strconv.Itoa(n)
+		}
+		l[i] = li
 	}
 	return l
 }
 
+// repeatsTitle reports whether desc is the title again, give or take case,
+// punctuation, markup and a trailing ellipsis.
+func repeatsTitle(title, desc string) bool {
+	t, d := foldForCompare(title), foldForCompare(plainText(desc))
+	if t == "" || d == "" {
+		return t == d
+	}
+	// Feeds that cut one down to fit leave a prefix of the other.
+	return (strings.HasPrefix(t, d) && 2*len(d) >= len(t)) ||
+		(strings.HasPrefix(d, t) && len(d)-len(t) <= 3)
+}
+
+// foldForCompare reduces s to lower case letters and digits.
+func foldForCompare(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
+
 // RSS parsing
 
 type RSS struct {
@@ -172,41 +864,282 @@ type RSSFeed struct {
 	Link          string    `xml:"link"`
 	Description   string    `xml:"description"`
 	LastBuildDate string    `xml:"lastBuildDate"`
+	TTL           string    `xml:"ttl"`
 	Items         []RSSItem `xml:"item"`
 }
 
+// ttl is how often the publisher asks to be polled, or 0 if they don't say.
+func (f RSSFeed) ttl() time.Duration {
+	n, err := strconv.Atoi(strings.TrimSpace(f.TTL))
+	if err != nil || n <= 0 {
+		return 0
+	}
+	return time.Duration(n) * time.Minute
+}
+
 type RSSItem struct {
 	Title       string `xml:"title"`
 	Link        string `xml:"link"`
 	Description string `xml:"description"`
 	Id          string `xml:"guid"`
+	// IsPermaLink is the guid's isPermaLink attribute as given, "" if absent.
+	IsPermaLink string `xml:"-"`
 	PublishDate string `xml:"pubDate"`
-	Creator     string `xml:"dc:creator"`
+	Creator     string `xml:"creator"` // dc:creator; encoding/xml matches on the local name
+	// Comments collects both the core <comments> link and slash:comments,
+	// which share a local name; commentCount and commentsLink tell them
+	// apart by namespace rather than by whatever prefix the feed picked.
+	Comments   []xmlText   `xml:"comments"`
+	CommentRss string      `xml:"http://wellformedweb.org/CommentAPI/ commentRss"`
+	Enclosures []enclosure `xml:"enclosure"`
+	// Raw is the item's XML as received, for the -debug view.
+	Raw string `xml:",innerxml"`
+}
+
+// UnmarshalXML decodes an item as usual, plus the guid's isPermaLink
+// attribute, which the struct tags can't reach alongside the guid text.
+func (i *RSSItem) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	type plainItem RSSItem
+	var aux struct {
+		plainItem
+		// Shadows plainItem.Id, being shallower.
+		GUID struct {
+			Value       string `xml:",chardata"`
+			IsPermaLink string `xml:"isPermaLink,attr"`
+		} `xml:"guid"`
+	}
+	if err := d.DecodeElement(&aux, &start); err != nil {
+		return err
+	}
+	*i = RSSItem(aux.plainItem)
+	i.Id = strings.TrimSpace(aux.GUID.Value)
+	i.IsPermaLink = aux.GUID.IsPermaLink
+	return nil
+}
+
+// permalink is where the item lives: its link, or failing that its guid
+// when that is a permalink. RSS says a guid is one unless isPermaLink is
+// "false", but plenty of feeds leave the attribute off opaque ids, so the
+// guid also has to look like a web address.
+func (i RSSItem) permalink() string {
+	if i.Link != "" {
+		return i.Link
+	}
+	if i.IsPermaLink == "false" {
+		return ""
+	}
+	u, err := url.Parse(i.Id)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ""
+	}
+	return i.Id
+}
+
+type xmlText struct {
+	XMLName xml.Name
+	Value   string `xml:",chardata"`
+}
+
+const slashNS = "http://purl.org/rss/1.0/modules/slash/"
+
+// commentCount returns the slash:comments count, if the feed gave a valid one.
+func (i RSSItem) commentCount() (int, bool) {
+	for _, c := range i.Comments {
+		if c.XMLName.Space != slashNS {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(c.Value))
+		return n, err == nil && n >= 0
+	}
+	return 0, false
+}
+
+// commentsLink prefers the core <comments> page over the wfw:commentRss feed.
+func (i RSSItem) commentsLink() string {
+	for _, c := range i.Comments {
+		if c.XMLName.Space == "" {
+			if link := strings.TrimSpace(c.Value); link != "" {
+				return link
+			}
+		}
+	}
+	return strings.TrimSpace(i.CommentRss)
+}
+
+// pubDateLayouts are the date formats seen in the wild, RFC 822 first since
+// that is what RSS specifies. Many feeds drop the leading zero of the day.
+var pubDateLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339,
+}
+
+// parsePubDate returns the zero time when s is missing or unparseable.
+func parsePubDate(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range pubDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
 }
 
-func scrapeUrlFeed(url string) (RSSFeed, error) {
-	httpClient := http.Client{Timeout: 2 * time.Second}
-	resp, err := httpClient.Get(url)
+// dedupItems drops repeated items, keyed strictly on GUID (or link when the
+// GUID is missing). Titles are deliberately not part of the key: feeds reuse
+// a headline for updates that point at different stories.
+func dedupItems(items []RSSItem) []RSSItem {
+	seen := make(map[string]bool, len(items))
+	out := make([]RSSItem, 0, len(items))
+	for _, item := range items {
+		if key := item.key(); key != "" {
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		out = append(out, item)
+	}
+	return out
+}
+
+var (
+	errInvalidFeedURL = errors.New("invalid feed URL")
+	errConnectTimeout = errors.New("timed out connecting to feed")
+	errReadTimeout    = errors.New("timed out waiting for feed to respond")
+)
+
+// newFeedClient returns a client for fetching feedURL, enforcing its -pin
+// if it has one.
+func newFeedClient(feedURL string) *http.Client {
+	t := &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           (&net.Dialer{Timeout: cfg.connectTimeout}).DialContext,
+		TLSHandshakeTimeout:   cfg.connectTimeout,
+		ResponseHeaderTimeout: cfg.readTimeout,
+	}
+	if pins, ok := cfg.pins[feedURL]; ok {
+		// validate has already rejected malformed pins.
+		sums, _ := parsePins(pins)
+		t.TLSClientConfig = &tls.Config{VerifyConnection: verifyPins(sums)}
+	}
+	c := &http.Client{
+		Transport: t,
+		// Backstop for the body, which neither transport timeout covers.
+		Timeout: cfg.fetchTimeout,
+	}
+	if t.TLSClientConfig != nil {
+		// A redirect to plain http would skip the pin check entirely.
+		c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if req.URL.Scheme != "https" {
+				return fmt.Errorf("%w: redirected to %s", errPinMismatch, req.URL)
+			}
+			return nil
+		}
+	}
+	return c
+}
+
+// classifyFetchError tells a feed that is slow to connect apart from one
+// that is slow to respond, so each timeout can be tuned on its own.
+func classifyFetchError(err error) error {
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		return err
+	}
+	var opErr *net.OpError
+	if (errors.As(err, &opErr) && opErr.Op == "dial") || strings.Contains(err.Error(), "TLS handshake timeout") {
+		return fmt.Errorf("%w after %s: %v", errConnectTimeout, cfg.connectTimeout, err)
+	}
+	return fmt.Errorf("%w: %v", errReadTimeout, err)
+}
+
+// normalizeFeedURL cleans up a pasted feed URL, percent-encoding stray
+// spaces, and rejects anything that isn't an absolute http(s) URL.
+func normalizeFeedURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", fmt.Errorf("%w %q: %v", errInvalidFeedURL, raw, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("%w %q: scheme must be http or https", errInvalidFeedURL, raw)
+	}
+	if u.Host == "" || strings.Contains(u.Host, " ") {
+		return "", fmt.Errorf("%w %q: missing or malformed host", errInvalidFeedURL, raw)
+	}
+	// String re-encodes the path but leaves the query as given.
+	u.RawQuery = strings.ReplaceAll(u.RawQuery, " ", "%20")
+	return u.String(), nil
+}
+
+func scrapeUrlFeed(rawURL string) (RSSFeed, error) {
+	feedURL, err := normalizeFeedURL(rawURL)
 	if err != nil {
 		return RSSFeed{}, err
 	}
-	defer resp.Body.Close()
-	data, err := io.ReadAll(resp.Body)
+	if wait := fetchLimit.reserve(); wait > 0 {
+		log.Info("Throttling feed fetch", "url", feedURL, "wait", wait.Round(time.Millisecond))
+		time.Sleep(wait)
+	}
+	if _, pinned := cfg.pins[feedURL]; pinned && !strings.HasPrefix(feedURL, "https:") {
+		return RSSFeed{}, fmt.Errorf("%w: %s is pinned but not https", errPinMismatch, feedURL)
+	}
+	ctx, cancel, stop := fetchContext()
+	defer cancel(nil)
+	defer stop()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
 	if err != nil {
 		return RSSFeed{}, err
 	}
+	if err := applyNetrc(req); err != nil {
+		return RSSFeed{}, err
+	}
+	client := newFeedClient(feedURL)
+	// ctx already carries the deadline, and says why it fired.
+	client.Timeout = 0
+	resp, err := client.Do(req)
+	if err != nil {
+		return RSSFeed{}, fetchError(ctx, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return RSSFeed{}, fmt.Errorf("feed returned %s", resp.Status)
+	}
+	data, err := readAtRate(resp.Body, cancel)
+	if err != nil {
+		return RSSFeed{}, fetchError(ctx, err)
+	}
+	switch format := detectFormat(resp.Header.Get("Content-Type"), data); format {
+	case formatRSS:
+	case formatUnknown:
+		return RSSFeed{}, errNotAFeed
+	default:
+		return RSSFeed{}, fmt.Errorf("unsupported feed format: %s", formatNames[format])
+	}
 	rss := RSS{}
 	if err := xml.Unmarshal(data, &rss); err != nil {
 		return RSSFeed{}, err
 	}
+	// Flattened first, so -filter-on all never sanitizes a description
+	// the guard would have refused.
+	rss.Channel.Items = filterItems(feedURL, flattenHeavyDescriptions(feedURL, rss.Channel.Items))
 	return rss.Channel, nil
 }
 
 // styles
 
 var (
-	docStyle   = lipgloss.NewStyle()
-	modalStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("63")).Padding(1, 2).Width(60).Align(lipgloss.Left)
+	docStyle         = lipgloss.NewStyle()
+	footerStyle      = lipgloss.NewStyle().Faint(true).PaddingLeft(2)
+	promptStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Bold(true)
+	plainDetailStyle = lipgloss.NewStyle().Padding(1, 2, 0)
+	bannerStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("0")).Background(lipgloss.Color("214")).Padding(0, 1)
+	motdStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("230")).Background(lipgloss.Color("62")).Padding(0, 1)
+	modalStyle       = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("63")).Padding(1, 2).Width(60).Align(lipgloss.Left)
 )
 
 // optional browser opening
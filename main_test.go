@@ -0,0 +1,706 @@
+package main
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// newFeedServer serves the fixtures in testdata along with the misbehaving
+// endpoints the fetcher needs to cope with. Tests never touch the network.
+func newFeedServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	fixture := func(name string) []byte {
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	serveAs := func(name, contentType string) http.HandlerFunc {
+		data := fixture(name)
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contentType)
+			w.Write(data)
+		}
+	}
+	serve := func(name string) http.HandlerFunc {
+		return serveAs(name, "application/rss+xml")
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/feed", serve("feed.xml"))
+	mux.Handle("/empty", serve("empty.xml"))
+	mux.Handle("/permalink", serve("permalink.xml"))
+	mux.Handle("/malformed", serve("malformed.xml"))
+	mux.Handle("/as-html", serveAs("feed.xml", "text/html; charset=utf-8"))
+	mux.Handle("/as-binary", serveAs("feed.xml", "application/octet-stream"))
+	mux.Handle("/atom-as-xml", serveAs("atom.xml", "text/xml"))
+	mux.Handle("/page", serveAs("page.html", "text/xml"))
+	mux.Handle("/moved", http.RedirectHandler("/feed", http.StatusMovedPermanently))
+	mux.HandleFunc("/gzip", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		gz.Write(fixture("feed.xml"))
+		gz.Close()
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+		}
+		serve("feed.xml")(w, r)
+	})
+	mux.HandleFunc("/trickle", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rss+xml")
+		for _, b := range fixture("feed.xml") {
+			w.Write([]byte{b})
+			w.(http.Flusher).Flush()
+			select {
+			case <-time.After(20 * time.Millisecond):
+			case <-r.Context().Done():
+				return
+			}
+		}
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// withConfig swaps in c for the duration of the test.
+func withConfig(t *testing.T, c config) {
+	t.Helper()
+	old := cfg
+	cfg = c
+	t.Cleanup(func() { cfg = old })
+}
+
+func TestScrapeUrlFeed(t *testing.T) {
+	srv := newFeedServer(t)
+	c := cfg
+	c.readTimeout = 100 * time.Millisecond
+	withConfig(t, c)
+
+	tests := []struct {
+		name      string
+		path      string
+		wantTitle string
+		wantItems int
+		wantErr   bool
+	}{
+		{name: "ok", path: "/feed", wantTitle: "Test Playbook", wantItems: 2},
+		{name: "gzip", path: "/gzip", wantTitle: "Test Playbook", wantItems: 2},
+		{name: "redirect", path: "/moved", wantTitle: "Test Playbook", wantItems: 2},
+		{name: "empty feed", path: "/empty", wantTitle: "Quiet Feed", wantItems: 0},
+		{name: "not found", path: "/missing", wantErr: true},
+		{name: "malformed xml", path: "/malformed", wantErr: true},
+		{name: "rss served as html", path: "/as-html", wantTitle: "Test Playbook", wantItems: 2},
+		{name: "rss served as octet-stream", path: "/as-binary", wantTitle: "Test Playbook", wantItems: 2},
+		{name: "atom served as xml", path: "/atom-as-xml", wantErr: true},
+		{name: "html served as xml", path: "/page", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed, err := scrapeUrlFeed(srv.URL + tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got feed %q", feed.Title)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if feed.Title != tt.wantTitle {
+				t.Errorf("title = %q, want %q", feed.Title, tt.wantTitle)
+			}
+			if len(feed.Items) != tt.wantItems {
+				t.Errorf("got %d items, want %d", len(feed.Items), tt.wantItems)
+			}
+		})
+	}
+}
+
+func TestScrapeUrlFeedTimeout(t *testing.T) {
+	srv := newFeedServer(t)
+	c := cfg
+	c.readTimeout = 50 * time.Millisecond
+	withConfig(t, c)
+
+	_, err := scrapeUrlFeed(srv.URL + "/slow")
+	if !errors.Is(err, errReadTimeout) {
+		t.Fatalf("err = %v, want %v", err, errReadTimeout)
+	}
+}
+
+func TestFeedToModel(t *testing.T) {
+	srv := newFeedServer(t)
+	feed, err := scrapeUrlFeed(srv.URL + "/feed")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := newModel(feed, userState{}, 80, 24)
+	if m.list.Title != "Test Playbook" {
+		t.Errorf("list title = %q", m.list.Title)
+	}
+	if m.homepage != "https://example.com/" {
+		t.Errorf("homepage = %q", m.homepage)
+	}
+	items := m.list.Items()
+	if len(items) != 2 {
+		t.Fatalf("got %d list items, want 2", len(items))
+	}
+	first := items[0].(rssListItem)
+	if first.title != "Senate passes budget" || first.link != "https://example.com/budget" || first.author != "Jane Reporter" {
+		t.Errorf("first item = %+v", first)
+	}
+	if first.comments != "12" || first.commentsLink != "https://example.com/budget#comments" {
+		t.Errorf("first item comments = %q, %q", first.comments, first.commentsLink)
+	}
+	second := items[1].(rssListItem)
+	if second.comments != "" || second.commentsLink != "https://example.com/bill/comments.xml" {
+		t.Errorf("second item comments = %q, %q", second.comments, second.commentsLink)
+	}
+	if media := second.media(); len(media) != 1 || media[0].URL != "https://example.com/bill.mp3" || media[0].Type != "audio/mpeg" {
+		t.Errorf("second item media = %+v", media)
+	}
+}
+
+func TestPermalinkGUID(t *testing.T) {
+	srv := newFeedServer(t)
+	feed, err := scrapeUrlFeed(srv.URL + "/permalink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"Explicit permalink": "https://example.com/explicit",
+		"Implied permalink":  "https://example.com/implied",
+		"Not a permalink":    "",
+		"Opaque id":          "",
+		"Link wins":          "https://example.com/link",
+	}
+	items := newModel(feed, userState{}, 80, 24).list.Items()
+	if len(items) != len(want) {
+		t.Fatalf("got %d items, want %d", len(items), len(want))
+	}
+	for _, it := range items {
+		item := it.(rssListItem)
+		if item.link != want[item.title] {
+			t.Errorf("%s: link = %q, want %q", item.title, item.link, want[item.title])
+		}
+	}
+	if got := feed.Items[0].IsPermaLink; got != "true" {
+		t.Errorf("isPermaLink = %q, want true", got)
+	}
+	if got := feed.Items[0].Raw; !strings.Contains(got, "<guid") {
+		t.Errorf("raw XML lost: %q", got)
+	}
+}
+
+func testFeed() RSSFeed {
+	return RSSFeed{
+		Title: "Test Playbook",
+		Link:  "https://example.com/",
+		Items: []RSSItem{
+			{Title: "Senate passes budget", Link: "https://example.com/budget", Id: "budget-1", Creator: "Jane Reporter", Description: "The vote was close."},
+			{Title: "Governor signs bill", Link: "https://example.com/bill", Id: "bill-1", Creator: "John Writer", Description: "It takes effect next year."},
+		},
+	}
+}
+
+func keyMsg(k string) tea.KeyMsg {
+	switch k {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "ctrl+c":
+		return tea.KeyMsg{Type: tea.KeyCtrlC}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+}
+
+// isQuit reports whether cmd, when run, asks the program to exit.
+func isQuit(cmd tea.Cmd) bool {
+	if cmd == nil {
+		return false
+	}
+	_, ok := cmd().(tea.QuitMsg)
+	return ok
+}
+
+func TestModelUpdate(t *testing.T) {
+	tests := []struct {
+		name  string
+		state userState
+		msgs  []tea.Msg
+		check func(t *testing.T, m model, cmd tea.Cmd)
+	}{
+		{
+			name: "enter opens detail",
+			msgs: []tea.Msg{keyMsg("enter")},
+			check: func(t *testing.T, m model, _ tea.Cmd) {
+				if !m.showDetail {
+					t.Error("detail not shown")
+				}
+				if m.selected.title != "Senate passes budget" {
+					t.Errorf("selected = %q", m.selected.title)
+				}
+			},
+		},
+		{
+			name: "esc closes detail",
+			msgs: []tea.Msg{keyMsg("enter"), keyMsg("esc")},
+			check: func(t *testing.T, m model, _ tea.Cmd) {
+				if m.showDetail {
+					t.Error("detail still shown")
+				}
+			},
+		},
+		{
+			name: "enter follows the cursor",
+			msgs: []tea.Msg{keyMsg("j"), keyMsg("enter")},
+			check: func(t *testing.T, m model, _ tea.Cmd) {
+				if m.selected.title != "Governor signs bill" {
+					t.Errorf("selected = %q", m.selected.title)
+				}
+			},
+		},
+		{
+			name: "+ shows more description lines",
+			msgs: []tea.Msg{keyMsg("+"), keyMsg("+")},
+			check: func(t *testing.T, m model, _ tea.Cmd) {
+				if m.state.DescLines != 3 || m.descLines() != 3 {
+					t.Errorf("DescLines = %d, descLines() = %d", m.state.DescLines, m.descLines())
+				}
+			},
+		},
+		{
+			name: "- hides descriptions",
+			msgs: []tea.Msg{keyMsg("-"), keyMsg("-")},
+			check: func(t *testing.T, m model, _ tea.Cmd) {
+				if m.state.DescLines != noDescLines || m.descLines() != 0 {
+					t.Errorf("DescLines = %d, descLines() = %d", m.state.DescLines, m.descLines())
+				}
+				if strings.Contains(m.View(), "The vote was close.") {
+					t.Error("description still shown")
+				}
+			},
+		},
+		{
+			name:  "description lines are capped",
+			state: userState{DescLines: maxDescLines},
+			msgs:  []tea.Msg{keyMsg("+")},
+			check: func(t *testing.T, m model, _ tea.Cmd) {
+				if m.descLines() != maxDescLines {
+					t.Errorf("descLines() = %d, want %d", m.descLines(), maxDescLines)
+				}
+			},
+		},
+		{
+			name: "i shows feed info",
+			msgs: []tea.Msg{keyMsg("i")},
+			check: func(t *testing.T, m model, _ tea.Cmd) {
+				if !m.showInfo {
+					t.Fatal("info not shown")
+				}
+				info := m.infoMarkdown()
+				for _, want := range []string{"Publisher TTL:** not given", "**Startup:** lazy", "up to 5m0s behind", "**Fallback:** none set"} {
+					if !strings.Contains(info, want) {
+						t.Errorf("info = %q, want it to mention %q", info, want)
+					}
+				}
+			},
+		},
+		{
+			name: "esc closes feed info",
+			msgs: []tea.Msg{keyMsg("i"), keyMsg("esc")},
+			check: func(t *testing.T, m model, _ tea.Cmd) {
+				if m.showInfo {
+					t.Error("info still shown")
+				}
+			},
+		},
+		{
+			name: "a toggles author",
+			msgs: []tea.Msg{keyMsg("a")},
+			check: func(t *testing.T, m model, _ tea.Cmd) {
+				if !m.state.HideAuthor {
+					t.Error("author still shown")
+				}
+			},
+		},
+		{
+			name: "f toggles focus mode in detail",
+			msgs: []tea.Msg{keyMsg("enter"), keyMsg("f")},
+			check: func(t *testing.T, m model, _ tea.Cmd) {
+				if !m.focusMode || !m.showDetail {
+					t.Error("focus mode not entered")
+				}
+				m.View()
+				r := m.focus.r
+				if m.View(); r == nil || m.focus.r != r {
+					t.Error("focus mode made a new renderer for the same width")
+				}
+				m.width = 60
+				if m.View(); m.focus.r == r || m.focus.width != 56 {
+					t.Errorf("renderer not remade for the new width, still %d", m.focus.width)
+				}
+			},
+		},
+		{
+			name: "r cycles the detail rendering",
+			msgs: []tea.Msg{keyMsg("enter"), keyMsg("r"), keyMsg("r")},
+			check: func(t *testing.T, m model, _ tea.Cmd) {
+				if m.detailMode != detailSource {
+					t.Fatalf("detailMode = %d, want source", m.detailMode)
+				}
+				if v := m.View(); !strings.Contains(v, "Showing HTML source") {
+					t.Errorf("footer doesn't show the mode: %q", v)
+				}
+				m.detailMode = m.detailMode.next()
+				m.View()
+				if len(m.renders) != 1 {
+					t.Errorf("got %d cached renders, want 1", len(m.renders))
+				}
+			},
+		},
+		{
+			name: "esc leaves focus mode for detail",
+			msgs: []tea.Msg{keyMsg("enter"), keyMsg("f"), keyMsg("esc")},
+			check: func(t *testing.T, m model, _ tea.Cmd) {
+				if m.focusMode || !m.showDetail {
+					t.Errorf("focusMode = %v, showDetail = %v", m.focusMode, m.showDetail)
+				}
+			},
+		},
+		{
+			name: "f pages the list outside detail",
+			msgs: []tea.Msg{keyMsg("f")},
+			check: func(t *testing.T, m model, _ tea.Cmd) {
+				if m.focusMode {
+					t.Error("focus mode entered from the list")
+				}
+			},
+		},
+		{
+			name: "p toggles headlines only",
+			msgs: []tea.Msg{keyMsg("p")},
+			check: func(t *testing.T, m model, _ tea.Cmd) {
+				if !m.peek || !strings.Contains(m.list.Title, "headlines only") {
+					t.Errorf("peek = %v, title = %q", m.peek, m.list.Title)
+				}
+			},
+		},
+		{
+			name: "q quits",
+			msgs: []tea.Msg{keyMsg("q")},
+			check: func(t *testing.T, _ model, cmd tea.Cmd) {
+				if !isQuit(cmd) {
+					t.Error("q did not quit")
+				}
+			},
+		},
+		{
+			name: "ctrl+c quits",
+			msgs: []tea.Msg{keyMsg("ctrl+c")},
+			check: func(t *testing.T, _ model, cmd tea.Cmd) {
+				if !isQuit(cmd) {
+					t.Error("ctrl+c did not quit")
+				}
+			},
+		},
+		{
+			name:  "confirm mode asks before quitting",
+			state: userState{QuitMode: quitConfirm},
+			msgs:  []tea.Msg{keyMsg("q")},
+			check: func(t *testing.T, m model, cmd tea.Cmd) {
+				if isQuit(cmd) || !m.confirmingQuit {
+					t.Error("q quit without asking")
+				}
+			},
+		},
+		{
+			name:  "confirm mode quits on y",
+			state: userState{QuitMode: quitConfirm},
+			msgs:  []tea.Msg{keyMsg("q"), keyMsg("y")},
+			check: func(t *testing.T, _ model, cmd tea.Cmd) {
+				if !isQuit(cmd) {
+					t.Error("y did not confirm")
+				}
+			},
+		},
+		{
+			name:  "confirm mode stays on other keys",
+			state: userState{QuitMode: quitConfirm},
+			msgs:  []tea.Msg{keyMsg("q"), keyMsg("j")},
+			check: func(t *testing.T, m model, cmd tea.Cmd) {
+				if isQuit(cmd) || m.confirmingQuit {
+					t.Error("quit prompt not dismissed")
+				}
+			},
+		},
+		{
+			name:  "ctrl+c only mode ignores q",
+			state: userState{QuitMode: quitCtrlCOnly},
+			msgs:  []tea.Msg{keyMsg("q")},
+			check: func(t *testing.T, _ model, cmd tea.Cmd) {
+				if isQuit(cmd) {
+					t.Error("q quit")
+				}
+			},
+		},
+		{
+			name:  "ctrl+c only mode still quits on ctrl+c",
+			state: userState{QuitMode: quitCtrlCOnly},
+			msgs:  []tea.Msg{keyMsg("ctrl+c")},
+			check: func(t *testing.T, _ model, cmd tea.Cmd) {
+				if !isQuit(cmd) {
+					t.Error("ctrl+c did not quit")
+				}
+			},
+		},
+		{
+			name: "fallback feed shows a banner",
+			msgs: []tea.Msg{tea.WindowSizeMsg{Width: 120, Height: 40}, feedMsg{feed: testFeed(), fallback: true}},
+			check: func(t *testing.T, m model, _ tea.Cmd) {
+				if !strings.Contains(m.View(), "showing a backup") {
+					t.Error("no fallback banner")
+				}
+				_, v := docStyle.GetFrameSize()
+				// One line each for the banner and the mode footer.
+				if m.list.Height() != 40-v-2 {
+					t.Errorf("list height = %d, want room for the banner", m.list.Height())
+				}
+			},
+		},
+		{
+			name: "resize updates list size",
+			msgs: []tea.Msg{tea.WindowSizeMsg{Width: 120, Height: 40}},
+			check: func(t *testing.T, m model, _ tea.Cmd) {
+				h, v := docStyle.GetFrameSize()
+				if m.list.Width() != 120-h || m.list.Height() != 40-v-1 {
+					t.Errorf("list size = %dx%d", m.list.Width(), m.list.Height())
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newModel(testFeed(), tt.state, 80, 24)
+			// Status messages tick for their lifetime when run by isQuit.
+			m.list.StatusMessageLifetime = time.Millisecond
+			var tm tea.Model = m
+			var cmd tea.Cmd
+			for _, msg := range tt.msgs {
+				tm, cmd = tm.Update(msg)
+			}
+			tt.check(t, tm.(model), cmd)
+		})
+	}
+}
+
+func TestNormalizeFeedURL(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "https://example.com/rss.xml", want: "https://example.com/rss.xml"},
+		{in: "  https://example.com/rss.xml\n", want: "https://example.com/rss.xml"},
+		{in: "https://example.com/my feed.xml", want: "https://example.com/my%20feed.xml"},
+		{in: "https://example.com/rss?topic=white house", want: "https://example.com/rss?topic=white%20house"},
+		{in: "https://example.com/%zz", wantErr: true},
+		{in: "example.com/rss.xml", wantErr: true},
+		{in: "ftp://example.com/rss.xml", wantErr: true},
+		{in: "https:///rss.xml", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := normalizeFeedURL(tt.in)
+		if tt.wantErr {
+			if !errors.Is(err, errInvalidFeedURL) {
+				t.Errorf("normalizeFeedURL(%q) err = %v, want %v", tt.in, err, errInvalidFeedURL)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("normalizeFeedURL(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestFeedTTL(t *testing.T) {
+	tests := []struct {
+		ttl  string
+		want time.Duration
+	}{
+		{"60", time.Hour},
+		{" 15 ", 15 * time.Minute},
+		{"", 0},
+		{"0", 0},
+		{"-5", 0},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		if got := (RSSFeed{TTL: tt.ttl}).ttl(); got != tt.want {
+			t.Errorf("ttl(%q) = %s, want %s", tt.ttl, got, tt.want)
+		}
+	}
+}
+
+func TestDedupItems(t *testing.T) {
+	items := dedupItems([]RSSItem{
+		{Title: "Senate passes budget", Id: "budget-1", Link: "https://example.com/a"},
+		{Title: "Senate passes budget (updated)", Id: "budget-1", Link: "https://example.com/b"},
+		{Title: "House votes", Link: "https://example.com/house"},
+		{Title: "House votes again", Link: "https://example.com/house"},
+		{Title: "Live updates", Link: "https://example.com/live-1"},
+		{Title: "Live updates", Link: "https://example.com/live-2"},
+		{Title: "No key"},
+		{Title: "No key"},
+	})
+	var links []string
+	for _, item := range items {
+		links = append(links, item.Link)
+	}
+	want := []string{"https://example.com/a", "https://example.com/house", "https://example.com/live-1", "https://example.com/live-2", "", ""}
+	if !slices.Equal(links, want) {
+		t.Fatalf("dedupItems kept %q, want %q", links, want)
+	}
+
+	l := toListItems(items)
+	if a, b := l[2].(rssListItem), l[3].(rssListItem); a.dupCount != 2 || a.dupIndex != 1 || b.dupIndex != 2 {
+		t.Errorf("shared titles marked %d/%d and %d/%d, want 1/2 and 2/2", a.dupIndex, a.dupCount, b.dupIndex, b.dupCount)
+	}
+}
+
+func TestRepeatsTitle(t *testing.T) {
+	tests := []struct {
+		title, desc string
+		want        bool
+	}{
+		{"Senate passes budget", "Senate passes budget", true},
+		{"Senate passes budget", "<p>Senate passes budget.</p>", true},
+		{"Senate passes budget", "SENATE PASSES BUDGET…", true},
+		{"Senate passes budget after all-night session", "Senate passes budget after all-night…", true},
+		{"Senate passes budget", "Senate passes budget after all-night session", false},
+		{"Senate passes budget", "Senate", false},
+		{"Senate passes budget", "The vote was close.", false},
+		{"Senate passes budget", "", false},
+	}
+	for _, tt := range tests {
+		if got := repeatsTitle(tt.title, tt.desc); got != tt.want {
+			t.Errorf("repeatsTitle(%q, %q) = %v, want %v", tt.title, tt.desc, got, tt.want)
+		}
+	}
+
+	items := toListItems([]RSSItem{{Title: "Senate passes budget", Description: "Senate passes budget."}})
+	for _, hide := range []bool{true, false} {
+		c := cfg
+		c.hideTitleDescriptions = hide
+		withConfig(t, c)
+		if got := items[0].(rssListItem).Description(); (got == "") != hide {
+			t.Errorf("hide = %v: description = %q", hide, got)
+		}
+	}
+}
+
+func TestParsePubDate(t *testing.T) {
+	want := time.Date(2025, 7, 1, 5, 0, 0, 0, time.UTC)
+	tests := []string{
+		"Tue, 01 Jul 2025 05:00:00 +0000",
+		"Tue, 1 Jul 2025 05:00:00 +0000",
+		" Tue, 01 Jul 2025 05:00:00 GMT ",
+		"2025-07-01T05:00:00Z",
+	}
+	for _, in := range tests {
+		if got := parsePubDate(in); !got.Equal(want) {
+			t.Errorf("parsePubDate(%q) = %v, want %v", in, got, want)
+		}
+	}
+	if got := parsePubDate("yesterday-ish"); !got.IsZero() {
+		t.Errorf("parsePubDate(garbage) = %v, want zero", got)
+	}
+}
+
+func TestFeedMsgLoadsList(t *testing.T) {
+	m := newModel(RSSFeed{}, userState{}, 80, 24)
+	m.loading = true
+	if m.Init() == nil {
+		t.Fatal("a loading model should fetch on Init")
+	}
+
+	tm, _ := m.Update(feedMsg{feed: testFeed()})
+	m = tm.(model)
+	if m.loading || len(m.list.Items()) != 2 || m.list.Title != "Test Playbook" {
+		t.Errorf("loading = %v, %d items, title %q", m.loading, len(m.list.Items()), m.list.Title)
+	}
+
+	m = newModel(RSSFeed{}, userState{}, 80, 24)
+	m.loading = true
+	tm, _ = m.Update(feedMsg{err: errReadTimeout})
+	if m = tm.(model); m.loading || !errors.Is(m.loadErr, errReadTimeout) {
+		t.Errorf("loading = %v, loadErr = %v", m.loading, m.loadErr)
+	}
+}
+
+func TestEmptyListKeys(t *testing.T) {
+	c := cfg
+	c.debug = true
+	withConfig(t, c)
+
+	for _, k := range []string{"enter", "o", " ", "r", "f", "x", "D", "s", "y", "n", "i", "t", "esc"} {
+		var m tea.Model = newModel(RSSFeed{Title: "Quiet Feed"}, userState{}, 80, 24)
+		m, _ = m.Update(keyMsg(k))
+		got := m.(model)
+		if got.showDetail || got.showRaw || got.showDiff {
+			t.Errorf("%q opened a view with nothing selected", k)
+		}
+		if got.selected.key() != "" || got.selected.title != "" {
+			t.Errorf("%q selected %+v", k, got.selected)
+		}
+		switch k {
+		case "enter", "x", "D", "y":
+			if v := got.View(); !strings.Contains(v, "No article selected") {
+				t.Errorf("%q gave no hint:\n%s", k, v)
+			}
+		}
+	}
+}
+
+func TestResizeDuringLoad(t *testing.T) {
+	var m tea.Model = newModel(RSSFeed{}, userState{}, 80, 24)
+	feed := testFeed()
+	for i := range 20 {
+		feed.Items = append(feed.Items, RSSItem{Title: fmt.Sprintf("Story %d", i), Link: fmt.Sprintf("https://example.com/%d", i)})
+	}
+	// The fetch is in flight when the client's window shrinks.
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 60, Height: 14})
+	m, _ = m.Update(feedMsg{feed: feed})
+	lm := m.(model)
+	// Less a line for the mode footer.
+	if lm.list.Width() != 60 || lm.list.Height() != 13 {
+		t.Errorf("list is %dx%d, want 60x13", lm.list.Width(), lm.list.Height())
+	}
+	if h := lipgloss.Height(lm.View()); h > 14 {
+		t.Errorf("view is %d lines tall in a 14 line window", h)
+	}
+	if lm.list.Paginator.TotalPages < 2 {
+		t.Errorf("%d pages for 22 items in 14 lines", lm.list.Paginator.TotalPages)
+	}
+}
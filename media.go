@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// enclosure is media attached to an item, typically a podcast episode.
+type enclosure struct {
+	URL  string `xml:"url,attr"`
+	Type string `xml:"type,attr"`
+	// Length is in bytes but kept as text: feeds get it wrong often enough
+	// that a bad one shouldn't fail the whole feed.
+	Length string `xml:"length,attr"`
+}
+
+// label names the media by its kind, e.g. "Audio" for audio/mpeg.
+func (e enclosure) label() string {
+	kind, _, _ := strings.Cut(e.Type, "/")
+	if kind == "" {
+		return "Media"
+	}
+	return strings.ToUpper(kind[:1]) + kind[1:]
+}
+
+// media returns the item's enclosures that point somewhere safeLink allows.
+func (r rssListItem) media() []enclosure {
+	var m []enclosure
+	for _, e := range r.rss.Enclosures {
+		if safeLink(e.URL) != "" {
+			m = append(m, e)
+		}
+	}
+	return m
+}
+
+// nextMedia moves the cursor to the next visible item with media, wrapping
+// to the top when there are none below it.
+func (m *model) nextMedia() tea.Cmd {
+	items := m.list.VisibleItems()
+	cur := m.list.Index()
+	for step := 1; step <= len(items); step++ {
+		i := (cur + step) % len(items)
+		if item, ok := items[i].(rssListItem); !ok || len(item.media()) == 0 {
+			continue
+		}
+		switch {
+		case i == cur:
+			return m.list.NewStatusMessage("No other items with media")
+		case i < cur:
+			m.list.Select(i)
+			return m.list.NewStatusMessage("No more media below, wrapped to the top")
+		}
+		m.list.Select(i)
+		return nil
+	}
+	return m.list.NewStatusMessage("No items with media")
+}
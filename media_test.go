@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextMedia(t *testing.T) {
+	audio := []enclosure{{URL: "https://example.com/ep.mp3", Type: "audio/mpeg"}}
+	feed := RSSFeed{Items: []RSSItem{
+		{Title: "Episode 1", Enclosures: audio},
+		{Title: "Show notes"},
+		{Title: "Episode 2", Enclosures: audio},
+		{Title: "Broken", Enclosures: []enclosure{{Type: "audio/mpeg"}}},
+	}}
+	m := newModel(feed, userState{}, 80, 40)
+	m.list.StatusMessageLifetime = time.Millisecond
+
+	tests := []struct {
+		want    int
+		message bool
+	}{
+		{2, false},
+		{0, true}, // past the end, and "Broken" has no URL
+		{2, false},
+	}
+	for i, tt := range tests {
+		cmd := m.nextMedia()
+		if got := m.list.Index(); got != tt.want {
+			t.Errorf("press %d: cursor at %d, want %d", i+1, got, tt.want)
+		}
+		if (cmd != nil) != tt.message {
+			t.Errorf("press %d: status message = %v, want %v", i+1, cmd != nil, tt.message)
+		}
+	}
+
+	m = newModel(RSSFeed{Items: []RSSItem{{Title: "Text only"}}}, userState{}, 80, 40)
+	if cmd := m.nextMedia(); cmd == nil || m.list.Index() != 0 {
+		t.Error("no message for a feed without media")
+	}
+}
+
+func TestEnclosureLabel(t *testing.T) {
+	tests := []struct{ typ, want string }{
+		{"audio/mpeg", "Audio"},
+		{"video/mp4", "Video"},
+		{"", "Media"},
+	}
+	for _, tt := range tests {
+		if got := (enclosure{Type: tt.typ}).label(); got != tt.want {
+			t.Errorf("label(%q) = %q, want %q", tt.typ, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/log"
+)
+
+// motd is the message of the day shown above the list.
+var motd *motdCache
+
+// motdMaxLines and motdMaxBytes keep a runaway remote message from taking
+// over the screen.
+const (
+	motdMaxLines = 3
+	motdMaxBytes = 4 << 10
+)
+
+// motdCache serves the message of the day from url, refetched in the
+// background, or the static one while the url can't be fetched.
+type motdCache struct {
+	url    string
+	static string
+
+	mu      sync.RWMutex
+	remote  string
+	fetched bool
+}
+
+func newMOTDCache(url, static string) *motdCache {
+	return &motdCache{url: url, static: cleanMOTD(static)}
+}
+
+// get returns the current message, or "" for none.
+func (c *motdCache) get() string {
+	if c == nil {
+		return ""
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.fetched {
+		return c.remote
+	}
+	return c.static
+}
+
+// refresh refetches the remote message. On failure the static one is used
+// until a fetch succeeds again.
+func (c *motdCache) refresh() {
+	if c.url == "" {
+		return
+	}
+	text, err := fetchMOTD(c.url)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		log.Warn("Failed to fetch message of the day, using the static one", "error", err)
+		c.fetched = false
+		return
+	}
+	c.remote, c.fetched = text, true
+}
+
+// run refreshes the message every interval until ctx is done.
+func (c *motdCache) run(ctx context.Context, interval time.Duration) {
+	if c.url == "" {
+		return
+	}
+	c.refresh()
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			c.refresh()
+		}
+	}
+}
+
+func fetchMOTD(url string) (string, error) {
+	resp, err := newFeedClient(url).Get(url)
+	if err != nil {
+		return "", classifyFetchError(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("message of the day returned %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, motdMaxBytes))
+	if err != nil {
+		return "", classifyFetchError(err)
+	}
+	return cleanMOTD(string(data)), nil
+}
+
+// cleanMOTD keeps the first motdMaxLines non-blank lines of s, without
+// control characters that could drive the client's terminal.
+func cleanMOTD(s string) string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(printable(line))
+		if line == "" {
+			continue
+		}
+		if lines = append(lines, line); len(lines) == motdMaxLines {
+			break
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// printable drops anything that isn't a printable character, such as the
+// escape sequences a remote string could use to drive the terminal.
+func printable(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsPrint(r) {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+// printableLines is printable for each line of s, keeping the breaks.
+func printableLines(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = printable(line)
+	}
+	return strings.Join(lines, "\n")
+}
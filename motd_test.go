@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCleanMOTD(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Breaking: budget vote at 3pm\n", "Breaking: budget vote at 3pm"},
+		{"\n\n  one \n\ntwo\nthree\nfour", "one\ntwo\nthree"},
+		{"evil\x1b[2Jclear", "evil[2Jclear"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := cleanMOTD(tt.in); got != tt.want {
+			t.Errorf("cleanMOTD(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMOTDCache(t *testing.T) {
+	var failing atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("Breaking: budget vote at 3pm\n"))
+	}))
+	defer srv.Close()
+
+	var none *motdCache
+	if got := none.get(); got != "" {
+		t.Errorf("nil cache = %q", got)
+	}
+	c := newMOTDCache(srv.URL, "Welcome")
+	if got := c.get(); got != "Welcome" {
+		t.Errorf("before the first fetch = %q, want the static message", got)
+	}
+	c.refresh()
+	if got := c.get(); got != "Breaking: budget vote at 3pm" {
+		t.Errorf("after a fetch = %q", got)
+	}
+	failing.Store(true)
+	c.refresh()
+	if got := c.get(); got != "Welcome" {
+		t.Errorf("after a failed fetch = %q, want the static message", got)
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// netrcEntry is one machine (or the default) from a .netrc file.
+type netrcEntry struct {
+	machine  string
+	login    string
+	password string
+}
+
+// netrcPath is $NETRC if set, else ~/.netrc, as curl and git look for it.
+func netrcPath() (string, error) {
+	if p := os.Getenv("NETRC"); p != "" {
+		return p, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".netrc"), nil
+}
+
+// parseNetrc reads machine, default, login and password tokens, skipping
+// account values and macdef bodies. The default entry, if any, has an
+// empty machine and only ever comes last. Errors never quote the file, so
+// they can't leak a password into the log; they count tokens instead.
+func parseNetrc(data string) ([]netrcEntry, error) {
+	var entries []netrcEntry
+	var cur *netrcEntry
+	sc := bufio.NewScanner(strings.NewReader(data))
+	inMacro := false
+	var tokens []string
+	for sc.Scan() {
+		line := sc.Text()
+		if inMacro {
+			// A macdef runs to the next blank line.
+			inMacro = strings.TrimSpace(line) != ""
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		for _, f := range strings.Fields(line) {
+			if f == "macdef" {
+				inMacro = true
+				break
+			}
+			tokens = append(tokens, f)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok == "default" {
+			entries = append(entries, netrcEntry{})
+			cur = &entries[len(entries)-1]
+			continue
+		}
+		if i+1 >= len(tokens) {
+			return nil, fmt.Errorf("netrc: token %d has no value", i+1)
+		}
+		val := tokens[i+1]
+		i++
+		switch tok {
+		case "machine":
+			entries = append(entries, netrcEntry{machine: val})
+			cur = &entries[len(entries)-1]
+		case "login", "password", "account":
+			if cur == nil {
+				return nil, fmt.Errorf("netrc: %s before any machine", tok)
+			}
+			switch tok {
+			case "login":
+				cur.login = val
+			case "password":
+				cur.password = val
+			}
+		default:
+			return nil, fmt.Errorf("netrc: token %d is not a keyword", i)
+		}
+	}
+	return entries, nil
+}
+
+// lookupNetrc finds the entry for host, falling back to the default.
+func lookupNetrc(entries []netrcEntry, host string) (netrcEntry, bool) {
+	var def *netrcEntry
+	for i, e := range entries {
+		if e.machine == "" {
+			if def == nil {
+				def = &entries[i]
+			}
+			continue
+		}
+		if strings.EqualFold(e.machine, host) {
+			return e, true
+		}
+	}
+	if def != nil {
+		return *def, true
+	}
+	return netrcEntry{}, false
+}
+
+// applyNetrc adds Basic auth to req from the .netrc entry for its host,
+// if -netrc is on and there is one. The file is read on every fetch so
+// rotated credentials take effect without a restart; a missing file just
+// means no credentials.
+func applyNetrc(req *http.Request) error {
+	if !cfg.netrc {
+		return nil
+	}
+	path, err := netrcPath()
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	entries, err := parseNetrc(string(data))
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	if e, ok := lookupNetrc(entries, req.URL.Hostname()); ok && e.login != "" {
+		req.SetBasicAuth(e.login, e.password)
+	}
+	return nil
+}
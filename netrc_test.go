@@ -0,0 +1,86 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseNetrc(t *testing.T) {
+	data := `# feeds
+machine feeds.example.com login reader password s3cret
+machine other.example.com
+	login bob
+	account ignored
+	password hunter2
+
+macdef init
+machine inside.macro login no password no
+
+default login anon password guest
+`
+	entries, err := parseNetrc(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		host, login, password string
+	}{
+		{"feeds.example.com", "reader", "s3cret"},
+		{"OTHER.example.com", "bob", "hunter2"},
+		{"inside.macro", "anon", "guest"},
+		{"unlisted.example.com", "anon", "guest"},
+	}
+	for _, tt := range tests {
+		e, ok := lookupNetrc(entries, tt.host)
+		if !ok || e.login != tt.login || e.password != tt.password {
+			t.Errorf("%s: got %+v, %v", tt.host, e, ok)
+		}
+	}
+	if _, ok := lookupNetrc(entries[:2], "unlisted.example.com"); ok {
+		t.Error("matched an unlisted host without a default")
+	}
+
+	for _, bad := range []string{"machine", "login bob", "machine x sekrit"} {
+		_, err := parseNetrc(bad)
+		if err == nil {
+			t.Errorf("%q: got nil error", bad)
+		} else if strings.Contains(err.Error(), "sekrit") {
+			t.Errorf("%q: error quotes the file: %v", bad, err)
+		}
+	}
+}
+
+func TestScrapeUrlFeedNetrc(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "feed.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "reader" || pass != "s3cret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+	path := filepath.Join(t.TempDir(), "netrc")
+	if err := os.WriteFile(path, []byte("machine 127.0.0.1 login reader password s3cret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("NETRC", path)
+
+	c := cfg
+	withConfig(t, c)
+	if _, err := scrapeUrlFeed(srv.URL); err == nil {
+		t.Fatal("fetched without -netrc")
+	}
+	cfg.netrc = true
+	if _, err := scrapeUrlFeed(srv.URL); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// palette is the : command palette, a fuzzy-filtered list of every action.
+type palette struct {
+	input textinput.Model
+	// matches indexes into actions, best match first.
+	matches []int
+	cursor  int
+}
+
+func newPalette() palette {
+	ti := textinput.New()
+	ti.Prompt = ": "
+	ti.Placeholder = "type a command"
+	ti.Focus()
+	p := palette{input: ti}
+	p.filter()
+	return p
+}
+
+func (p *palette) filter() {
+	p.matches = p.matches[:0]
+	var usable []int
+	for i, a := range actions {
+		if a.available() {
+			usable = append(usable, i)
+		}
+	}
+	term := p.input.Value()
+	if term == "" {
+		p.matches = append(p.matches, usable...)
+	} else {
+		names := make([]string, len(usable))
+		for i, idx := range usable {
+			names[i] = actions[idx].key.Help().Desc
+		}
+		for _, r := range list.DefaultFilter(term, names) {
+			p.matches = append(p.matches, usable[r.Index])
+		}
+	}
+	p.cursor = min(p.cursor, max(len(p.matches)-1, 0))
+}
+
+// updatePalette handles a key while the palette is open.
+func (m *model) updatePalette(msg tea.KeyMsg) tea.Cmd {
+	p := &m.palette
+	switch msg.String() {
+	case "esc":
+		m.showPalette = false
+		return nil
+	case "enter":
+		m.showPalette = false
+		if len(p.matches) == 0 {
+			return nil
+		}
+		return actions[p.matches[p.cursor]].run(m)
+	case "up", "ctrl+p":
+		p.cursor = max(p.cursor-1, 0)
+		return nil
+	case "down", "ctrl+n":
+		p.cursor = min(p.cursor+1, max(len(p.matches)-1, 0))
+		return nil
+	}
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	p.filter()
+	return cmd
+}
+
+func (p palette) View() string {
+	var b strings.Builder
+	b.WriteString(p.input.View())
+	b.WriteString("\n\n")
+	if len(p.matches) == 0 {
+		b.WriteString(paletteDimStyle.Render("No matching commands"))
+	}
+	for i, idx := range p.matches {
+		help := actions[idx].key.Help()
+		line := fmt.Sprintf("%-28s %s", help.Desc, paletteDimStyle.Render(help.Key))
+		if i == p.cursor {
+			line = paletteSelectedStyle.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+var (
+	paletteSelectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("170"))
+	paletteDimStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+)
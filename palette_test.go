@@ -0,0 +1,93 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPalette(t *testing.T) {
+	type step = []tea.Msg
+	typed := func(s string) step {
+		var msgs step
+		for _, r := range s {
+			msgs = append(msgs, keyMsg(string(r)))
+		}
+		return msgs
+	}
+	run := func(msgs ...step) model {
+		var tm tea.Model = newModel(testFeed(), userState{}, 80, 24)
+		for _, s := range msgs {
+			for _, msg := range s {
+				tm, _ = tm.Update(msg)
+			}
+		}
+		return tm.(model)
+	}
+
+	m := run(step{keyMsg(":")}, typed("author"), step{keyMsg("enter")})
+	if m.showPalette {
+		t.Error("palette still open after running a command")
+	}
+	if !m.state.HideAuthor {
+		t.Error("toggle author did not run")
+	}
+
+	m = run(step{keyMsg(":")}, typed("q"), step{keyMsg("esc")})
+	if m.showPalette {
+		t.Error("esc did not close the palette")
+	}
+
+	m = run(step{keyMsg(":")}, typed("zzzz"))
+	if len(m.palette.matches) != 0 {
+		t.Errorf("got %d matches for nonsense", len(m.palette.matches))
+	}
+
+	// q typed into the palette filters rather than quits.
+	m = run(step{keyMsg(":")}, typed("q"))
+	if !m.showPalette || m.palette.input.Value() != "q" {
+		t.Error("q was not typed into the palette")
+	}
+}
+
+func TestDetailActionsListed(t *testing.T) {
+	helpKeys := func() map[string]bool {
+		got := map[string]bool{}
+		for _, b := range fullHelp() {
+			got[b.Help().Key] = true
+		}
+		p := newPalette()
+		for _, i := range p.matches {
+			if !got[actions[i].key.Help().Key] && !actions[i].listKey {
+				t.Errorf("%q is in the palette but not the help", actions[i].key.Help().Key)
+			}
+		}
+		return got
+	}
+	got := helpKeys()
+	for _, k := range []string{"enter", "o", "f", "r", "space"} {
+		if !got[k] {
+			t.Errorf("%s is missing from the help", k)
+		}
+	}
+	if got["x"] {
+		t.Error("x is listed without -debug")
+	}
+	c := cfg
+	c.debug = true
+	withConfig(t, c)
+	if !helpKeys()["x"] {
+		t.Error("x is missing from the help with -debug")
+	}
+
+	// From the palette, outside the detail view, they say what they need.
+	var tm tea.Model = newModel(testFeed(), userState{}, 80, 24)
+	for _, msg := range []tea.Msg{keyMsg(":"), keyMsg("f"), keyMsg("o"), keyMsg("c"), keyMsg("u"), keyMsg("s"), keyMsg("enter")} {
+		tm, _ = tm.Update(msg)
+	}
+	m := tm.(model)
+	if m.focusMode || !strings.Contains(m.View(), "Open an article first") {
+		t.Errorf("focus mode ran outside the detail view:\n%s", m.View())
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var errPinMismatch = errors.New("feed certificate does not match its pin")
+
+// parsePins reads a -pin value: comma-separated base64 SHA-256 hashes of a
+// certificate's SubjectPublicKeyInfo, each optionally prefixed "sha256/"
+// as printed by e.g. `openssl x509 -pubkey | openssl pkey -pubin -outform
+// der | openssl dgst -sha256 -binary | base64`.
+func parsePins(s string) ([][]byte, error) {
+	var pins [][]byte
+	for _, p := range strings.Split(s, ",") {
+		p = strings.TrimPrefix(strings.TrimSpace(p), "sha256/")
+		sum, err := base64.StdEncoding.DecodeString(p)
+		if err != nil {
+			// Some tools print the hash without its '=' padding.
+			sum, err = base64.RawStdEncoding.DecodeString(p)
+		}
+		if err != nil || len(sum) != sha256.Size {
+			return nil, fmt.Errorf("pin %q is not a base64 SHA-256 hash", p)
+		}
+		pins = append(pins, sum)
+	}
+	return pins, nil
+}
+
+// verifyPins fails any connection where no certificate the server sent
+// has one of pins as its SPKI hash. Pinning an intermediate works as well
+// as pinning the leaf, and outlives the leaf's renewals.
+func verifyPins(pins [][]byte) func(tls.ConnectionState) error {
+	return func(cs tls.ConnectionState) error {
+		for _, cert := range cs.PeerCertificates {
+			sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+			for _, pin := range pins {
+				if bytes.Equal(sum[:], pin) {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("%w (%s)", errPinMismatch, cs.ServerName)
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestParsePins(t *testing.T) {
+	sum := sha256.Sum256([]byte("key"))
+	pin := base64.StdEncoding.EncodeToString(sum[:])
+	tests := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{"sha256/" + pin, 1, true},
+		{pin + ", sha256/" + pin, 2, true},
+		{"sha256/notbase64!", 0, false},
+		{base64.StdEncoding.EncodeToString([]byte("short")), 0, false},
+		{"", 0, false},
+	}
+	for _, tt := range tests {
+		pins, err := parsePins(tt.in)
+		if (err == nil) != tt.ok || len(pins) != tt.want {
+			t.Errorf("parsePins(%q) = %d pins, %v", tt.in, len(pins), err)
+		}
+	}
+}
+
+func TestVerifyPins(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	cert := srv.Certificate()
+	match := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	other := sha256.Sum256([]byte("someone else"))
+	cs := tls.ConnectionState{ServerName: "example.com", PeerCertificates: []*x509.Certificate{cert}}
+
+	if err := verifyPins([][]byte{other[:], match[:]})(cs); err != nil {
+		t.Errorf("matching pin rejected: %v", err)
+	}
+	if err := verifyPins([][]byte{other[:]})(cs); !errors.Is(err, errPinMismatch) {
+		t.Errorf("mismatched pin: got %v, want errPinMismatch", err)
+	}
+}
+
+func TestPinnedFeedIsHTTPSOnly(t *testing.T) {
+	srv := newFeedServer(t)
+	sum := sha256.Sum256([]byte("key"))
+	c := cfg
+	c.pins = perFeed{srv.URL + "/feed": "sha256/" + base64.StdEncoding.EncodeToString(sum[:])}
+	withConfig(t, c)
+	if _, err := scrapeUrlFeed(srv.URL + "/feed"); !errors.Is(err, errPinMismatch) {
+		t.Errorf("pinned http feed: got %v, want errPinMismatch", err)
+	}
+}
+
+func TestPinFlag(t *testing.T) {
+	sum := sha256.Sum256([]byte("key"))
+	padded := "sha256/" + base64.StdEncoding.EncodeToString(sum[:])
+	if !strings.HasSuffix(padded, "=") {
+		t.Fatalf("test pin %q has no padding", padded)
+	}
+	unpadded := "sha256/" + base64.RawStdEncoding.EncodeToString(sum[:])
+
+	c := cfg
+	c.pins, c.include = perFeed{}, perFeed{}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	c.registerFlags(fs)
+	err := fs.Parse([]string{
+		"-pin", "https://example.com/feed=" + padded,
+		"-pin", "https://example.com/raw.xml=" + unpadded,
+		"-pin", "https://example.com/rss?section=politics=" + padded + "," + unpadded,
+		"-include", "https://example.com/feed=a=b",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := perFeed{
+		"https://example.com/feed":                 padded,
+		"https://example.com/raw.xml":              unpadded,
+		"https://example.com/rss?section=politics": padded + "," + unpadded,
+	}
+	if !maps.Equal(c.pins, want) {
+		t.Errorf("pins = %v, want %v", c.pins, want)
+	}
+	if got := c.include["https://example.com/feed"]; got != "a=b" {
+		t.Errorf("include = %q, want a=b", got)
+	}
+	for url, v := range c.pins {
+		pins, err := parsePins(v)
+		if err != nil {
+			t.Errorf("%s: %v", url, err)
+			continue
+		}
+		for _, pin := range pins {
+			if !bytes.Equal(pin, sum[:]) {
+				t.Errorf("%s: parsed pin %x, want %x", url, pin, sum)
+			}
+		}
+	}
+}
+
+func TestPinKeysNormalized(t *testing.T) {
+	sum := sha256.Sum256([]byte("key"))
+	pin := "sha256/" + base64.StdEncoding.EncodeToString(sum[:])
+
+	c := cfg
+	c.feedURL = "HTTPS://example.com/my feed.xml"
+	c.pins = perFeed{"HTTPS://example.com/my feed.xml": pin}
+	if err := c.validate(); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.pins[c.feedURL]; !ok {
+		t.Errorf("pin for %s not found under its normalized URL in %v", c.feedURL, c.pins)
+	}
+
+	c = cfg
+	c.pins = perFeed{"https://other.example.com/feed": pin}
+	if err := c.validate(); err == nil || !strings.Contains(err.Error(), "-pin") {
+		t.Errorf("pin for an unconfigured feed: got %v, want a -pin error", err)
+	}
+}
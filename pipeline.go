@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// The list is built in stages: the feed's items, then the session's own
+// filters below, then day headers, and only then the list's text filter,
+// which searches whatever the earlier stages left. Keeping the session's
+// filters out of the list's means neither has to know about the other,
+// and clearing one never undoes the other.
+
+// sessionFilter is one of the session's own filters.
+type sessionFilter struct {
+	// label says what the filter keeps, for the footer.
+	label string
+	keep  func(RSSItem) bool
+}
+
+// sessionFilters are the filters the session has switched on, in the
+// order they apply.
+func (m model) sessionFilters() []sessionFilter {
+	var fs []sessionFilter
+	if m.authorFilter != "" {
+		author := m.authorFilter
+		fs = append(fs, sessionFilter{
+			label: "by " + author,
+			keep:  func(item RSSItem) bool { return item.Creator == author },
+		})
+	}
+	return fs
+}
+
+// filteredItems is m.items through the session's filters.
+func (m model) filteredItems() []RSSItem {
+	fs := m.sessionFilters()
+	if len(fs) == 0 {
+		return m.items
+	}
+	var out []RSSItem
+	for _, item := range m.items {
+		keep := true
+		for _, f := range fs {
+			keep = keep && f.keep(item)
+		}
+		if keep {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// toggleAuthorFilter shows only the current article's author, or everyone
+// again. The cursor stays on the same article.
+func (m *model) toggleAuthorFilter() tea.Cmd {
+	item, ok := m.current()
+	switch {
+	case m.authorFilter != "":
+		m.authorFilter = ""
+	case !ok:
+		return m.noSelection()
+	case item.author == "":
+		return m.list.NewStatusMessage("This article doesn't name its author")
+	default:
+		m.authorFilter = item.author
+	}
+	m.setItems()
+	m.reselect(item)
+	return nil
+}
+
+// setListItems hands items to the list and, if a search is active, runs
+// it over them straight away. The list would otherwise leave that to a
+// command, showing no results until it comes back and ignoring any
+// cursor moves made in the meantime.
+func (m *model) setListItems(items []list.Item) {
+	if cmd := m.list.SetItems(items); cmd != nil {
+		m.list, _ = m.list.Update(cmd())
+	}
+}
+
+// reselect moves the cursor back to item after the list was rebuilt, if
+// it is still there.
+func (m *model) reselect(item rssListItem) {
+	for i, it := range m.list.VisibleItems() {
+		if r, ok := it.(rssListItem); ok && r.key() == item.key() {
+			m.list.Select(i)
+			break
+		}
+	}
+	m.skipHeader(0)
+}
+
+// filterLabel lists the filters narrowing the list, or "" for none. When
+// together they leave nothing, it says which to drop, so an empty list
+// reads as the filters' doing rather than the feed's.
+func (m model) filterLabel() string {
+	var parts []string
+	for _, f := range m.sessionFilters() {
+		parts = append(parts, f.label)
+	}
+	if m.list.FilterState() != list.Unfiltered {
+		parts = append(parts, fmt.Sprintf("%s matching %q", scopeNames[m.searchScope], m.list.FilterValue()))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	label := "Showing " + strings.Join(parts, ", ")
+	if m.authorFilter != "" && m.list.FilterState() != list.Unfiltered && len(m.list.VisibleItems()) == 0 {
+		label += " (nothing left: esc clears the search, A shows every author)"
+	}
+	return label
+}
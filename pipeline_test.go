@@ -0,0 +1,88 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func pipelineFeed() RSSFeed {
+	feed := testFeed()
+	feed.Items = append(feed.Items, RSSItem{
+		Title: "Senate adjourns", Link: "https://example.com/adjourn", Id: "adjourn-1", Creator: "Jane Reporter",
+	})
+	return feed
+}
+
+func visibleTitles(m model) []string {
+	var titles []string
+	for _, it := range m.list.VisibleItems() {
+		if item, ok := it.(rssListItem); ok {
+			titles = append(titles, item.title)
+		}
+	}
+	return titles
+}
+
+func TestAuthorFilterWithSearch(t *testing.T) {
+	m := newModel(pipelineFeed(), userState{}, 100, 30)
+	press := func(k string) {
+		t.Helper()
+		mm, _ := m.Update(keyMsg(k))
+		m = mm.(model)
+	}
+
+	m.list.SetFilterText("senate")
+	press("A")
+	if m.authorFilter != "Jane Reporter" {
+		t.Fatalf("authorFilter = %q, want Jane Reporter", m.authorFilter)
+	}
+	// The search still applies, to what the author filter left.
+	got := visibleTitles(m)
+	slices.Sort(got)
+	if strings.Join(got, ",") != "Senate adjourns,Senate passes budget" {
+		t.Errorf("visible = %q, want both of Jane's Senate stories", got)
+	}
+	footer := ansi.Strip(m.footer())
+	if !strings.Contains(footer, "by Jane Reporter") || !strings.Contains(footer, `title matching "senate"`) {
+		t.Errorf("footer %q doesn't list both filters", footer)
+	}
+
+	m.list.SetFilterText("governor")
+	if got := visibleTitles(m); len(got) != 0 {
+		t.Errorf("visible = %q, want nothing", got)
+	}
+	if footer := ansi.Strip(m.footer()); !strings.Contains(footer, "nothing left") {
+		t.Errorf("footer %q doesn't explain the empty list", footer)
+	}
+
+	// Esc clears only the search, A only the author filter.
+	press("esc")
+	if m.list.FilterState() != list.Unfiltered {
+		t.Errorf("after esc the filter is %v, want unfiltered", m.list.FilterState())
+	}
+	if got := visibleTitles(m); len(got) != 2 {
+		t.Errorf("after esc visible = %q, want Jane's two", got)
+	}
+	press("A")
+	if got := visibleTitles(m); len(got) != 3 {
+		t.Errorf("after A visible = %q, want all three", got)
+	}
+	if footer := ansi.Strip(m.footer()); strings.Contains(footer, "Showing") {
+		t.Errorf("footer %q still lists filters", footer)
+	}
+}
+
+func TestAuthorFilterKeepsCursor(t *testing.T) {
+	m := newModel(pipelineFeed(), userState{}, 100, 30)
+	m.list.Select(2)
+	if cmd := m.toggleAuthorFilter(); cmd != nil {
+		t.Fatal("unexpected status message")
+	}
+	if item, _ := m.list.SelectedItem().(rssListItem); item.title != "Senate adjourns" {
+		t.Errorf("selected %q, want Senate adjourns", item.title)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"golang.org/x/net/html"
+)
+
+// previewMaxBytes is as much of a page as we read looking for its Open
+// Graph tags, which belong in the head anyway.
+const previewMaxBytes = 512 << 10
+
+// linkPreview is a page's Open Graph summary.
+type linkPreview struct {
+	Title, Description, Image string
+}
+
+func (p linkPreview) empty() bool {
+	return p.Title == "" && p.Description == "" && p.Image == ""
+}
+
+// previewMsg carries the preview of link; failures arrive as an empty one
+// so they are cached too and never retried within the session.
+type previewMsg struct {
+	link    string
+	preview linkPreview
+}
+
+// fetchPreview fetches the selected link's preview once per session, if
+// -link-preview is on and the session isn't in lite mode.
+func (m *model) fetchPreview(link string) tea.Cmd {
+	if !cfg.linkPreview || m.lite || link == "" {
+		return nil
+	}
+	if _, ok := m.previews[link]; ok {
+		return nil
+	}
+	if m.previews == nil {
+		m.previews = map[string]linkPreview{}
+	}
+	// Reserve the entry so opening the item again doesn't refetch it.
+	m.previews[link] = linkPreview{}
+	return func() tea.Msg {
+		p, _ := fetchLinkPreview(link)
+		return previewMsg{link, p}
+	}
+}
+
+// errPreviewBlocked is returned for previews that would reach into the
+// server's own network.
+var errPreviewBlocked = errors.New("preview address not allowed")
+
+// previewAddrOK reports whether a preview may connect to ip. Links come
+// from the feed, so without this anyone who can get one into it could
+// have the server probe its loopback, private or link-local neighbours.
+var previewAddrOK = func(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast() && !ip.IsUnspecified()
+}
+
+// checkPreviewURL rejects anything but an http(s) link.
+func checkPreviewURL(u *url.URL) error {
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("not a web link: %q", u)
+	}
+	return nil
+}
+
+// newPreviewClient returns a client that only fetches web links on public
+// addresses. The address is checked as each connection is made, after DNS,
+// so neither a redirect nor a host resolving somewhere private gets past
+// it. It never uses a proxy, which would hide the real destination.
+func newPreviewClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: cfg.connectTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			ap, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !previewAddrOK(ap.Addr()) {
+				return fmt.Errorf("%w: %s", errPreviewBlocked, ap.Addr())
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   cfg.connectTimeout,
+			ResponseHeaderTimeout: cfg.readTimeout,
+		},
+		Timeout: cfg.fetchTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return checkPreviewURL(req.URL)
+		},
+	}
+}
+
+func fetchLinkPreview(link string) (linkPreview, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return linkPreview{}, fmt.Errorf("not a web link: %q", link)
+	}
+	if err := checkPreviewURL(u); err != nil {
+		return linkPreview{}, err
+	}
+	resp, err := newPreviewClient().Get(link)
+	if err != nil {
+		return linkPreview{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return linkPreview{}, fmt.Errorf("preview returned %s", resp.Status)
+	}
+	return parseOpenGraph(io.LimitReader(resp.Body, previewMaxBytes)), nil
+}
+
+// parseOpenGraph reads og:title, og:description and og:image from a page,
+// stopping at the end of its head.
+func parseOpenGraph(r io.Reader) linkPreview {
+	var p linkPreview
+	z := html.NewTokenizer(r)
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return p
+		case html.EndTagToken:
+			if name, _ := z.TagName(); string(name) == "head" {
+				return p
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			t := z.Token()
+			if t.Data != "meta" {
+				continue
+			}
+			content := strings.TrimSpace(attr(t, "content"))
+			// Open Graph says property, but plenty of sites write name.
+			prop := attr(t, "property")
+			if prop == "" {
+				prop = attr(t, "name")
+			}
+			switch prop {
+			case "og:title":
+				p.Title = content
+			case "og:description":
+				p.Description = content
+			case "og:image":
+				p.Image = content
+			}
+		}
+	}
+}
+
+// previewMarkdown renders the selected item's preview, or "" if there is
+// nothing to add.
+func (m model) previewMarkdown() string {
+	p := m.previews[m.selected.link]
+	if p.empty() {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("---\n\n")
+	if p.Title != "" {
+		fmt.Fprintf(&b, "**%s**\n\n", escapeMarkdown(printable(p.Title)))
+	}
+	if p.Description != "" {
+		fmt.Fprintf(&b, "%s\n\n", escapeMarkdown(printable(p.Description)))
+	}
+	if u, err := url.Parse(p.Image); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		fmt.Fprintf(&b, "[Image](%s)\n\n", linkDest(p.Image))
+	}
+	return strings.TrimSuffix(b.String(), "\n\n")
+}
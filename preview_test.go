@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseOpenGraph(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "article.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	want := linkPreview{
+		Title:       "Senate passes budget after all-night session",
+		Description: "Lawmakers approved the spending plan 51-49.",
+		Image:       "https://example.com/budget.jpg",
+	}
+	if got := parseOpenGraph(f); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+// allowPreviewAddrs lets previews reach addresses that ok accepts, such as
+// the loopback test servers listen on.
+func allowPreviewAddrs(t *testing.T, ok func(netip.Addr) bool) {
+	saved := previewAddrOK
+	previewAddrOK = ok
+	t.Cleanup(func() { previewAddrOK = saved })
+}
+
+func TestLinkPreview(t *testing.T) {
+	page, err := os.ReadFile(filepath.Join("testdata", "article.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(page)
+	}))
+	defer srv.Close()
+	c := cfg
+	c.linkPreview = true
+	withConfig(t, c)
+	allowPreviewAddrs(t, func(netip.Addr) bool { return true })
+
+	m := newModel(testFeed(), userState{}, 80, 40)
+	for _, tt := range []struct{ path, want string }{
+		{"/budget", "all-night session"},
+		{"/missing", ""},
+	} {
+		m.selected = rssListItem{title: "Budget", link: srv.URL + tt.path}
+		cmd := m.fetchPreview(m.selected.link)
+		if cmd == nil {
+			t.Fatalf("%s: no fetch", tt.path)
+		}
+		tm, _ := m.Update(cmd())
+		m = tm.(model)
+		if m.fetchPreview(m.selected.link) != nil {
+			t.Errorf("%s: fetched twice", tt.path)
+		}
+		got := m.previewMarkdown()
+		if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+			t.Errorf("%s: preview = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("got %d requests, want one per link", requests)
+	}
+}
+
+func TestLinkPreviewOff(t *testing.T) {
+	m := newModel(testFeed(), userState{}, 80, 40)
+	if m.fetchPreview("https://example.com/budget") != nil {
+		t.Error("fetched a preview without -link-preview")
+	}
+}
+
+func TestLinkPreviewBlocked(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/to-ftp":
+			http.Redirect(w, r, "ftp://example.com/", http.StatusFound)
+		case "/to-localhost":
+			// A different host, so the client has to dial again.
+			http.Redirect(w, r, strings.Replace(srv.URL, "127.0.0.1", "localhost", 1)+"/", http.StatusFound)
+		}
+	}))
+	defer srv.Close()
+
+	for _, link := range []string{"file:///etc/passwd", "gopher://example.com/", "http:///nohost"} {
+		if _, err := fetchLinkPreview(link); err == nil || !strings.Contains(err.Error(), "not a web link") {
+			t.Errorf("%s: err = %v, want it refused as not a web link", link, err)
+		}
+	}
+	if _, err := fetchLinkPreview(srv.URL + "/"); !errors.Is(err, errPreviewBlocked) {
+		t.Errorf("loopback: err = %v, want %v", err, errPreviewBlocked)
+	}
+	for _, ip := range []string{"10.1.2.3", "192.168.0.1", "169.254.169.254", "::1", "fe80::1", "fd00::1", "0.0.0.0", "::ffff:127.0.0.1"} {
+		if previewAddrOK(netip.MustParseAddr(ip)) {
+			t.Errorf("%s allowed", ip)
+		}
+	}
+	if !previewAddrOK(netip.MustParseAddr("93.184.216.34")) {
+		t.Error("public address refused")
+	}
+
+	// Let only the first connection through, so anything after a redirect
+	// has to pass the check on its own.
+	dials := 0
+	allowPreviewAddrs(t, func(netip.Addr) bool { dials++; return dials == 1 })
+	if _, err := fetchLinkPreview(srv.URL + "/to-localhost"); !errors.Is(err, errPreviewBlocked) {
+		t.Errorf("redirect to loopback: err = %v, want %v", err, errPreviewBlocked)
+	}
+	dials = 0
+	if _, err := fetchLinkPreview(srv.URL + "/to-ftp"); err == nil || !strings.Contains(err.Error(), "not a web link") {
+		t.Errorf("redirect to ftp: err = %v, want it refused", err)
+	}
+}
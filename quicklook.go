@@ -0,0 +1,121 @@
+package main
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+const (
+	// quickLookWidth caps the popover, border included.
+	quickLookWidth = 56
+	// quickLookLines is how much of the description the popover shows.
+	quickLookLines = 3
+	// quickLookIndent clears the selection bar at the left of the list.
+	quickLookIndent = 4
+)
+
+// toggleQuickLook shows the item under the cursor in a popover next to it,
+// leaving the list in view. From the detail view it collapses the modal
+// into the popover instead.
+func (m *model) toggleQuickLook() tea.Cmd {
+	if m.showDetail {
+		m.showDetail, m.focusMode = false, false
+		m.quickLook = true
+		return nil
+	}
+	if _, ok := m.current(); !ok && !m.quickLook {
+		return m.noSelection()
+	}
+	m.quickLook = !m.quickLook
+	return nil
+}
+
+// quickLookBox is the popover for item: its title, the start of its
+// description and its link.
+func quickLookBox(item rssListItem, width int) string {
+	inner := width - quickLookStyle.GetHorizontalFrameSize()
+	desc := ansi.Wrap(printable(strings.Join(strings.Fields(plainText(item.desc)), " ")), inner, "")
+	if lines := strings.Split(desc, "\n"); len(lines) > quickLookLines {
+		lines = lines[:quickLookLines]
+		lines[quickLookLines-1] = ansi.Truncate(lines[quickLookLines-1], inner-1, "") + "…"
+		desc = strings.Join(lines, "\n")
+	}
+	parts := []string{quickLookTitleStyle.Render(ansi.Wrap(printable(item.title), inner, ""))}
+	if desc != "" {
+		parts = append(parts, desc)
+	}
+	if item.link != "" {
+		parts = append(parts, quickLookLinkStyle.Render(ansi.Truncate(printable(item.link), inner, "…")))
+	}
+	return quickLookStyle.Width(width - quickLookStyle.GetHorizontalBorderSize()).Render(strings.Join(parts, "\n\n"))
+}
+
+// withQuickLook draws the popover over listView, just under the selected
+// item, or above it when there is no room below. top is the row the list
+// starts on.
+func (m model) withQuickLook(listView string, top int) string {
+	item, ok := m.list.SelectedItem().(rssListItem)
+	if !ok {
+		return listView
+	}
+	width := min(quickLookWidth, m.width-quickLookIndent)
+	if width < quickLookStyle.GetHorizontalFrameSize()+10 {
+		// Too narrow to anchor it anywhere useful; show it under the
+		// list like the other modals.
+		return listView + "\n\n" + quickLookBox(item, quickLookWidth)
+	}
+	box := quickLookBox(item, width)
+	d := newItemDelegate(&m)
+	row := top + m.listHeaderHeight() +
+		(m.list.Index()-m.list.Paginator.Page*m.list.Paginator.PerPage)*(d.Height()+d.Spacing())
+	y := row + d.Height()
+	if h := lipgloss.Height(box); y+h > lipgloss.Height(listView) && row-h >= top {
+		y = row - h
+	}
+	// Place pads the box out to where it should sit; overlay then lays it
+	// over the list a line at a time, since lipgloss has no layers.
+	return overlay(listView, lipgloss.PlaceHorizontal(quickLookIndent+width, lipgloss.Right, box), y)
+}
+
+// listHeaderHeight is how many lines the list draws above its first item.
+func (m model) listHeaderHeight() int {
+	h := 0
+	if m.list.ShowTitle() || (m.list.ShowFilter() && m.list.FilteringEnabled()) {
+		h += 1 + m.list.Styles.TitleBar.GetVerticalFrameSize()
+	}
+	if m.list.ShowStatusBar() {
+		h += 1 + m.list.Styles.StatusBar.GetVerticalFrameSize()
+	}
+	return h
+}
+
+// overlay writes box over base from row y down. Where box is only
+// whitespace, as in the padding from lipgloss.Place, base shows through.
+func overlay(base, box string, y int) string {
+	lines := strings.Split(base, "\n")
+	for i, l := range strings.Split(box, "\n") {
+		row := y + i
+		if row < 0 {
+			continue
+		}
+		for row >= len(lines) {
+			lines = append(lines, "")
+		}
+		body := strings.TrimLeft(l, " ")
+		x := len(l) - len(body)
+		left := ansi.Truncate(lines[row], x, "")
+		left += strings.Repeat(" ", x-ansi.StringWidth(left))
+		right := ansi.TruncateLeft(lines[row], ansi.StringWidth(l), "")
+		lines[row] = left + ansi.ResetStyle + body + ansi.ResetStyle + right
+	}
+	return strings.Join(lines, "\n")
+}
+
+var (
+	quickLookStyle      = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("63")).Padding(0, 1)
+	quickLookTitleStyle = lipgloss.NewStyle().Bold(true)
+	quickLookLinkStyle  = lipgloss.NewStyle().Faint(true)
+)
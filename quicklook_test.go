@@ -0,0 +1,77 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestQuickLook(t *testing.T) {
+	m := newModel(testFeed(), userState{}, 80, 24)
+	press := func(k string) {
+		t.Helper()
+		mm, _ := m.Update(keyMsg(k))
+		m = mm.(model)
+	}
+	press("j")
+	press("v")
+	if !m.quickLook {
+		t.Fatal("v didn't open the quick look")
+	}
+	lines := strings.Split(ansi.Strip(m.View()), "\n")
+	byline := -1
+	for i, l := range lines {
+		if strings.Contains(l, "Governor signs bill · John Writer") {
+			byline = i
+		}
+	}
+	if byline < 0 {
+		t.Fatal("selected item not in view")
+	}
+	// Two lines for the item, then the popover's border and title.
+	if got := lines[byline+2]; !strings.Contains(got, "╭") {
+		t.Errorf("line under the item is %q, want the popover's top", got)
+	}
+	if got := lines[byline+3]; !strings.Contains(got, "Governor signs bill") {
+		t.Errorf("popover starts with %q, want the title", got)
+	}
+	if !strings.Contains(ansi.Strip(m.View()), "https://example.com/bill") {
+		t.Error("popover doesn't show the link")
+	}
+
+	press("esc")
+	if m.quickLook {
+		t.Error("esc didn't close the quick look")
+	}
+
+	press("enter")
+	press("v")
+	if m.showDetail || !m.quickLook {
+		t.Errorf("v in the detail view: showDetail %v, quickLook %v; want the popover only", m.showDetail, m.quickLook)
+	}
+	press("enter")
+	if !m.showDetail || m.quickLook {
+		t.Errorf("enter from the popover: showDetail %v, quickLook %v; want the modal only", m.showDetail, m.quickLook)
+	}
+}
+
+func TestQuickLookStripsEscapes(t *testing.T) {
+	item := rssListItem{
+		title: "Budget\x1b]52;c;aGk=\a vote",
+		desc:  "<p>The vote &#27;]52;c;aGk=&#7;was close.</p>",
+		link:  "https://example.com/\x1b[2Jbudget",
+	}
+	if box := quickLookBox(item, quickLookWidth); strings.Contains(box, "\a") || strings.Contains(box, "\x1b]") || strings.Contains(box, "\x1b[2J") {
+		t.Errorf("quick look lets escapes through: %q", box)
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	base := "aaaaaa\nbbbbbb\ncccccc"
+	got := ansi.Strip(overlay(base, "  XY\n  ZW\n  UV", 1))
+	want := "aaaaaa\nbbXYbb\nccZWcc\n  UV"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// fetchLimit throttles every upstream feed request; nil means no limit.
+var fetchLimit *tokenBucket
+
+// tokenBucket allows burst requests at once and rate per second after
+// that. Callers that can't wait check available first; the rest reserve a
+// token and sleep for as long as they are told, which queues them in
+// arrival order.
+type tokenBucket struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket returns a full bucket, or nil if perMinute is not positive.
+func newTokenBucket(perMinute float64, burst int) *tokenBucket {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &tokenBucket{rate: perMinute / 60, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// fill must be called with mu held.
+func (b *tokenBucket) fill() {
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+}
+
+// available reports whether a request could go out right now.
+func (b *tokenBucket) available() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.fill()
+	return b.tokens >= 1
+}
+
+// reserve takes a token and returns how long to wait before using it.
+func (b *tokenBucket) reserve() time.Duration {
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.fill()
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenBucket(t *testing.T) {
+	var unlimited *tokenBucket
+	if !unlimited.available() || unlimited.reserve() != 0 {
+		t.Error("nil bucket throttled")
+	}
+	if newTokenBucket(0, 1) != nil {
+		t.Error("zero rate should mean no limit")
+	}
+
+	b := newTokenBucket(60, 2)
+	for i := range 2 {
+		if wait := b.reserve(); wait != 0 {
+			t.Fatalf("request %d within burst waited %s", i, wait)
+		}
+	}
+	if b.available() {
+		t.Error("available after the burst was spent")
+	}
+	if wait := b.reserve(); wait < 900*time.Millisecond || wait > time.Second {
+		t.Errorf("third request waits %s, want about 1s", wait)
+	}
+	if wait := b.reserve(); wait < 1900*time.Millisecond || wait > 2*time.Second {
+		t.Errorf("fourth request waits %s, want about 2s behind the third", wait)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+)
+
+// searchScope picks which fields the list filter matches against. It is
+// cycled with tab while typing a filter.
+type searchScope int
+
+const (
+	scopeTitle searchScope = iota
+	scopeAuthor
+	scopeAll
+)
+
+var scopeNames = [...]string{"title", "author", "all"}
+
+func (s searchScope) next() searchScope {
+	return (s + 1) % searchScope(len(scopeNames))
+}
+
+func (s searchScope) prompt() string {
+	return fmt.Sprintf("Filter %s (tab to change): ", scopeNames[s])
+}
+
+// setSearchScope switches the filter to s and refilters if a filter is active.
+func (m *model) setSearchScope(s searchScope) {
+	m.searchScope = s
+	m.list.FilterInput.Prompt = s.prompt()
+	m.list.Filter = scopedFilter(s)
+	items := m.list.Items()
+	for i, item := range items {
+		if r, ok := item.(rssListItem); ok {
+			r.scope = s
+			items[i] = r
+		}
+	}
+	m.setListItems(items)
+}
+
+// scopeValue is what the filter matches for r. The title leads the full-text
+// value so match positions within it can still be highlighted.
+func (r rssListItem) scopeValue() string {
+	switch r.scope {
+	case scopeAuthor:
+		return r.author
+	case scopeAll:
+		return r.title + "\n" + r.author + "\n" + sanitizePolicies["strict"].Sanitize(r.desc)
+	}
+	return r.title
+}
+
+// scopedFilter is the list's fuzzy (and case-insensitive) filter, minus match
+// positions outside the title: those would highlight the wrong runes, since
+// only the title is highlighted.
+func scopedFilter(s searchScope) list.FilterFunc {
+	if s == scopeTitle {
+		return list.DefaultFilter
+	}
+	return func(term string, targets []string) []list.Rank {
+		ranks := list.DefaultFilter(term, targets)
+		for i, r := range ranks {
+			titleLen := 0
+			if s == scopeAll {
+				title, _, _ := strings.Cut(targets[r.Index], "\n")
+				titleLen = len(title)
+			}
+			var matched []int
+			for _, idx := range r.MatchedIndexes {
+				if idx < titleLen {
+					matched = append(matched, idx)
+				}
+			}
+			ranks[i].MatchedIndexes = matched
+		}
+		return ranks
+	}
+}
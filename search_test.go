@@ -0,0 +1,68 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSearchScope(t *testing.T) {
+	tests := []struct {
+		scope searchScope
+		term  string
+		want  []string
+	}{
+		{scope: scopeTitle, term: "senate", want: []string{"Senate passes budget"}},
+		{scope: scopeTitle, term: "jane", want: nil},
+		{scope: scopeAuthor, term: "JOHN", want: []string{"Governor signs bill"}},
+		{scope: scopeAuthor, term: "budget", want: nil},
+		{scope: scopeAll, term: "next year", want: []string{"Governor signs bill"}},
+	}
+	for _, tt := range tests {
+		t.Run(scopeNames[tt.scope]+"/"+tt.term, func(t *testing.T) {
+			m := newModel(testFeed(), userState{}, 80, 24)
+			m.setSearchScope(tt.scope)
+			m.list.SetFilterText(tt.term)
+			var got []string
+			for _, item := range m.list.VisibleItems() {
+				got = append(got, item.(rssListItem).title)
+			}
+			if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTabCyclesSearchScope(t *testing.T) {
+	var tm tea.Model = newModel(testFeed(), userState{}, 80, 24)
+	tm, _ = tm.Update(keyMsg("/"))
+	tm, _ = tm.Update(tea.KeyMsg{Type: tea.KeyTab})
+	m := tm.(model)
+	if m.searchScope != scopeAuthor {
+		t.Fatalf("scope = %v, want author", scopeNames[m.searchScope])
+	}
+	if m.list.FilterInput.Prompt != scopeAuthor.prompt() {
+		t.Errorf("prompt = %q", m.list.FilterInput.Prompt)
+	}
+}
+
+func TestFilterDisambiguatesSharedTitles(t *testing.T) {
+	feed := testFeed()
+	feed.Items = []RSSItem{
+		{Title: "Budget update", Link: "https://example.com/1", Creator: "Jane Reporter", PublishDate: "Mon, 02 Jun 2025 09:30:00 +0000"},
+		{Title: "Budget update", Link: "https://example.com/2", Creator: "John Writer", PublishDate: "Mon, 02 Jun 2025 17:45:00 +0000"},
+	}
+	m := newModel(feed, userState{HideAuthor: true}, 80, 24)
+	if v := m.View(); strings.Contains(v, "Jun 2") {
+		t.Fatalf("unfiltered list shows dates:\n%s", v)
+	}
+	m.list.SetFilterText("BUDGET")
+	v := m.View()
+	for _, want := range []string{"Jun 2 09:30 · Jane Reporter", "Jun 2 17:45 · John Writer"} {
+		if !strings.Contains(v, want) {
+			t.Errorf("filtered rows don't show %q:\n%s", want, v)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+)
+
+// errFeedTooSlow is returned when a feed host sends its response too
+// slowly, whether by taking past -fetch-timeout overall or by trickling
+// the body out below -min-fetch-rate.
+var errFeedTooSlow = errors.New("feed too slow")
+
+// errFeedTooLarge is returned when a feed's body is over -max-feed-size.
+var errFeedTooLarge = errors.New("feed too large")
+
+// fetchContext bounds a whole fetch, body included, by -fetch-timeout. Its
+// cancel func also lets readAtRate abort the fetch early.
+func fetchContext() (context.Context, context.CancelCauseFunc, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	ctx, stop := context.WithTimeoutCause(ctx, cfg.fetchTimeout,
+		fmt.Errorf("%w: no complete response within %s", errFeedTooSlow, cfg.fetchTimeout))
+	return ctx, cancel, stop
+}
+
+// countingReader counts the bytes read through it so far.
+type countingReader struct {
+	r io.Reader
+	n atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	return n, err
+}
+
+// readLimited reads body to the end, failing with errFeedTooLarge once it
+// is past cfg.maxFeedSize rather than reading on.
+func readLimited(body io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(body, cfg.maxFeedSize+1))
+	if err == nil && int64(len(data)) > cfg.maxFeedSize {
+		return nil, fmt.Errorf("%w: over %d bytes", errFeedTooLarge, cfg.maxFeedSize)
+	}
+	return data, err
+}
+
+// readAtRate reads body to the end, up to cfg.maxFeedSize, calling
+// cancel with errFeedTooSlow if fewer than cfg.minFetchRate bytes a
+// second arrive over any cfg.minRateWindow. Checking over a window rather
+// than per read lets a host pause briefly without being cut off.
+func readAtRate(body io.Reader, cancel context.CancelCauseFunc) ([]byte, error) {
+	if cfg.minFetchRate <= 0 {
+		return readLimited(body)
+	}
+	cr := &countingReader{r: body}
+	done := make(chan struct{})
+	defer close(done)
+	// The watchdog works from copies: it can still be running after
+	// readAtRate returns, when cfg may have changed.
+	window := cfg.minRateWindow
+	floor := int64(cfg.minFetchRate * window.Seconds())
+	go func() {
+		t := time.NewTicker(window)
+		defer t.Stop()
+		var last int64
+		for {
+			select {
+			case <-done:
+				return
+			case <-t.C:
+				n := cr.n.Load()
+				if n-last < floor {
+					cancel(fmt.Errorf("%w: %d bytes in the last %s, want at least %d",
+						errFeedTooSlow, n-last, window, floor))
+					return
+				}
+				last = n
+			}
+		}
+	}()
+	return readLimited(cr)
+}
+
+// fetchError prefers the reason the fetch context gave up, if it did, over
+// whatever the transport made of being cut off.
+func fetchError(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); errors.Is(cause, errFeedTooSlow) {
+		return cause
+	}
+	return classifyFetchError(err)
+}
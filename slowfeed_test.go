@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestScrapeUrlFeedTooSlow(t *testing.T) {
+	srv := newFeedServer(t)
+	tests := []struct {
+		name     string
+		timeout  time.Duration
+		minRate  float64
+		wantText string
+	}{
+		{name: "overall deadline", timeout: 150 * time.Millisecond, wantText: "no complete response within 150ms"},
+		{name: "below minimum rate", timeout: time.Minute, minRate: 1000, wantText: "in the last 50ms"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := cfg
+			c.fetchTimeout = tt.timeout
+			c.minFetchRate = tt.minRate
+			c.minRateWindow = 50 * time.Millisecond
+			withConfig(t, c)
+
+			start := time.Now()
+			_, err := scrapeUrlFeed(srv.URL + "/trickle")
+			if !errors.Is(err, errFeedTooSlow) {
+				t.Fatalf("err = %v, want %v", err, errFeedTooSlow)
+			}
+			if !strings.Contains(err.Error(), tt.wantText) {
+				t.Errorf("err = %q, want it to mention %q", err, tt.wantText)
+			}
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Errorf("took %s to give up", elapsed)
+			}
+		})
+	}
+}
+
+func TestReadAtRateTooLarge(t *testing.T) {
+	for _, rate := range []float64{0, 1} {
+		c := cfg
+		c.maxFeedSize = 6
+		c.minFetchRate = rate
+		withConfig(t, c)
+
+		if data, err := readAtRate(strings.NewReader("<rss/>"), func(error) {}); err != nil || string(data) != "<rss/>" {
+			t.Errorf("rate %g: body at the limit: got %q, %v", rate, data, err)
+		}
+		if _, err := readAtRate(strings.NewReader("<rss />"), func(error) {}); !errors.Is(err, errFeedTooLarge) {
+			t.Errorf("rate %g: body over the limit: err = %v, want %v", rate, err, errFeedTooLarge)
+		}
+	}
+}
+
+func TestReadAtRateFastBody(t *testing.T) {
+	c := cfg
+	c.minFetchRate = 1 << 20
+	c.minRateWindow = 10 * time.Millisecond
+	withConfig(t, c)
+
+	var cancelled atomic.Bool
+	data, err := readAtRate(strings.NewReader("<rss/>"), func(error) { cancelled.Store(true) })
+	if err != nil || string(data) != "<rss/>" {
+		t.Fatalf("got %q, %v", data, err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if cancelled.Load() {
+		t.Error("cancelled a body that had already been read")
+	}
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// itemOrders are the keys -sort accepts, each comparing two items.
+var itemOrders = map[string]func(a, b RSSItem) int{
+	// date is newest first; undated items go last.
+	"date": func(a, b RSSItem) int {
+		x, y := parsePubDate(a.PublishDate), parsePubDate(b.PublishDate)
+		if x.IsZero() || y.IsZero() {
+			return boolCompare(x.IsZero(), y.IsZero())
+		}
+		return y.Compare(x)
+	},
+	"title": func(a, b RSSItem) int {
+		return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	},
+	// author is alphabetical; items without a byline go last.
+	"author": func(a, b RSSItem) int {
+		if a.Creator == "" || b.Creator == "" {
+			return boolCompare(a.Creator == "", b.Creator == "")
+		}
+		return strings.Compare(strings.ToLower(a.Creator), strings.ToLower(b.Creator))
+	},
+}
+
+// boolCompare orders false before true.
+func boolCompare(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	}
+	return -1
+}
+
+// parseSortKeys reads -sort, a comma-separated list of itemOrders keys
+// from most to least significant.
+func parseSortKeys(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var keys []string
+	for _, k := range strings.Split(s, ",") {
+		k = strings.TrimSpace(k)
+		if _, ok := itemOrders[k]; !ok {
+			return nil, fmt.Errorf("unknown sort key %q", k)
+		}
+		if slices.Contains(keys, k) {
+			return nil, fmt.Errorf("sort key %q given twice", k)
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// sortItems orders a copy of items by keys in turn. The sort is stable, so
+// items that tie on every key keep the feed's order.
+func sortItems(items []RSSItem, keys []string) []RSSItem {
+	if len(keys) == 0 {
+		return items
+	}
+	sorted := slices.Clone(items)
+	slices.SortStableFunc(sorted, func(a, b RSSItem) int {
+		for _, k := range keys {
+			if c := itemOrders[k](a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	return sorted
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSortItems(t *testing.T) {
+	const morning, evening = "Tue, 01 Jul 2025 08:00:00 +0000", "Tue, 01 Jul 2025 20:00:00 +0000"
+	items := []RSSItem{
+		{Title: "c", PublishDate: morning, Creator: "Zoe"},
+		{Title: "a", PublishDate: evening, Creator: "Max"},
+		{Title: "b", PublishDate: morning, Creator: "Ann"},
+		{Title: "d", Creator: "Ann"},
+		{Title: "e", PublishDate: evening},
+	}
+	tests := []struct {
+		sort string
+		want string
+	}{
+		{"", "cabde"},
+		{"date", "aecbd"},
+		{"date,author", "aebcd"},
+		{"author", "bdace"},
+		{"author,date", "bdace"},
+		{"title", "abcde"},
+	}
+	for _, tt := range tests {
+		keys, err := parseSortKeys(tt.sort)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got strings.Builder
+		for _, item := range sortItems(items, keys) {
+			got.WriteString(item.Title)
+		}
+		if got.String() != tt.want {
+			t.Errorf("-sort %q: got %s, want %s", tt.sort, got.String(), tt.want)
+		}
+	}
+	if items[0].Title != "c" {
+		t.Error("sortItems modified its input")
+	}
+}
+
+func TestParseSortKeys(t *testing.T) {
+	for _, bad := range []string{"date,popularity", "date,date", "date,"} {
+		if _, err := parseSortKeys(bad); err == nil {
+			t.Errorf("parseSortKeys(%q): got nil error", bad)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// defaultSplash is shown while the first fetch runs, unless -splash names
+// a file to use instead.
+const defaultSplash = `             _ _ _   _
+ _ __   ___ | (_) |_(_) ___ ___   _ __   _____      _____
+| '_ \ / _ \| | | __| |/ __/ __| | '_ \ / _ \ \ /\ / / __|
+| |_) | (_) | | | |_| | (__\__ \_| | | |  __/\ V  V /\__ \
+| .__/ \___/|_|_|\__|_|\___|___(_)_| |_|\___| \_/\_/ |___/
+|_|
+
+The day's politics, over ssh.`
+
+// splashMaxLines and splashMaxBytes keep a -splash file to a screenful.
+const (
+	splashMaxLines = 20
+	splashMaxBytes = 4 << 10
+)
+
+// loadSplash reads a -splash file, keeping only what can be printed.
+func loadSplash(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if len(data) > splashMaxBytes {
+		return "", fmt.Errorf("%s is over %d bytes", path, splashMaxBytes)
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) > splashMaxLines {
+		return "", fmt.Errorf("%s is over %d lines", path, splashMaxLines)
+	}
+	for i, line := range lines {
+		lines[i] = printable(strings.ReplaceAll(line, "\t", "    "))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// splashDoneMsg ends the splash when -splash-time is up.
+type splashDoneMsg struct{}
+
+// holdSplash keeps the splash up for -splash-time even when the feed is
+// already in, so every session opens the same way. Lite sessions, which
+// skip timers, go straight to the list.
+func (m *model) holdSplash() {
+	m.showSplash = cfg.splashTime > 0 && !m.lite
+}
+
+// splashTimer ends a held splash; see holdSplash.
+func (m model) splashTimer() tea.Cmd {
+	if !m.showSplash {
+		return nil
+	}
+	return tea.Tick(cfg.splashTime, func(time.Time) tea.Msg { return splashDoneMsg{} })
+}
+
+// splashView is the splash centered in the window, with what it is
+// waiting for under it.
+func (m model) splashView() string {
+	status := "Press any key to continue."
+	if m.loading {
+		status = "Loading feed…"
+	}
+	var body string
+	// Art cut to fit a narrow window would only be noise.
+	if cfg.splash != "" && (m.width <= 0 || lipgloss.Width(cfg.splash) <= m.width) {
+		body = splashStyle.Render(cfg.splash) + "\n\n"
+	}
+	body += splashStatusStyle.Render(status)
+	if m.width <= 0 || m.height <= 0 {
+		return docStyle.Render(body)
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, body)
+}
+
+var (
+	splashStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("63"))
+	splashStatusStyle = lipgloss.NewStyle().Faint(true)
+)
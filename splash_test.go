@@ -0,0 +1,78 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestLoadSplash(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	got, err := loadSplash(write("ok.txt", "\x1b[31mNEWS\x1b[0m\n\tdaily\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "[31mNEWS[0m\n    daily"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if _, err := loadSplash(write("tall.txt", strings.Repeat("x\n", splashMaxLines+1))); err == nil {
+		t.Error("over-long splash: got nil error")
+	}
+	if _, err := loadSplash(filepath.Join(dir, "missing.txt")); err == nil {
+		t.Error("missing splash: got nil error")
+	}
+}
+
+func TestSplash(t *testing.T) {
+	c := cfg
+	c.splashTime = time.Second
+	withConfig(t, c)
+
+	m := newModel(testFeed(), userState{}, 80, 24)
+	m.loading = true
+	view := ansi.Strip(m.View())
+	if !strings.Contains(view, "The day's politics, over ssh.") || !strings.Contains(view, "Loading feed…") {
+		t.Errorf("loading view has no splash:\n%s", view)
+	}
+
+	m.loading = false
+	m.holdSplash()
+	if m.splashTimer() == nil {
+		t.Fatal("held splash has no timer")
+	}
+	tm, _ := m.Update(keyMsg("j"))
+	if m = tm.(model); m.showSplash || m.list.Index() != 0 {
+		t.Errorf("a key should only dismiss the splash: showSplash %v, index %d", m.showSplash, m.list.Index())
+	}
+
+	m.holdSplash()
+	tm, _ = m.Update(splashDoneMsg{})
+	if m = tm.(model); m.showSplash {
+		t.Error("splash still up after its time")
+	}
+
+	m.lite = true
+	if m.holdSplash(); m.showSplash {
+		t.Error("lite session held the splash")
+	}
+
+	c.splash = ""
+	withConfig(t, c)
+	m.loading = true
+	if view := ansi.Strip(m.View()); strings.TrimSpace(view) != "Loading feed…" {
+		t.Errorf("without art the view is %q", view)
+	}
+}
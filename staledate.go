@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// dateWarning says what is wrong with an item's publish date, or "" if
+// nothing is: it is more than cfg.futureSlack ahead of now, which points
+// at a skewed clock or a bad feed, or older than cfg.maxItemAge, which
+// news rarely is. Items without a date get no warning.
+func dateWarning(published, now time.Time) string {
+	if published.IsZero() {
+		return ""
+	}
+	date := published.Format("Jan 2, 2006 15:04 MST")
+	switch age := now.Sub(published); {
+	case -age > cfg.futureSlack:
+		return fmt.Sprintf("Dated %s, which is in the future; the feed's clock or data may be off.", date)
+	case cfg.maxItemAge > 0 && age > cfg.maxItemAge:
+		return fmt.Sprintf("Dated %s, which is old for news; the feed may be serving stale items.", date)
+	}
+	return ""
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDateWarning(t *testing.T) {
+	c := cfg
+	c.futureSlack = 15 * time.Minute
+	c.maxItemAge = 30 * 24 * time.Hour
+	withConfig(t, c)
+
+	now := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		published time.Time
+		want      string
+	}{
+		{"undated", time.Time{}, ""},
+		{"recent", now.Add(-time.Hour), ""},
+		{"within slack", now.Add(10 * time.Minute), ""},
+		{"future", now.Add(time.Hour), "in the future"},
+		{"a week old", now.AddDate(0, 0, -7), ""},
+		{"too old", now.AddDate(0, -2, 0), "old for news"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := dateWarning(tt.published, now)
+			if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	c.maxItemAge = 0
+	withConfig(t, c)
+	if got := dateWarning(now.AddDate(-10, 0, 0), now); got != "" {
+		t.Errorf("with -max-item-age 0: got %q, want no warning", got)
+	}
+}
+
+func TestDateWarningShown(t *testing.T) {
+	feed := testFeed()
+	feed.Items[1].PublishDate = time.Now().Add(48 * time.Hour).Format(time.RFC1123Z)
+	m := newModel(feed, userState{}, 80, 24)
+	items := m.list.Items()
+	if title := items[0].(rssListItem).Title(); strings.Contains(title, "⚠") {
+		t.Errorf("undated item title %q has a warning", title)
+	}
+	item := items[1].(rssListItem)
+	if !strings.HasSuffix(item.Title(), " ⚠") {
+		t.Errorf("future item title %q has no warning", item.Title())
+	}
+	m.selected = item
+	if md := m.detailMarkdown(); !strings.Contains(md, "in the future") {
+		t.Errorf("detail view doesn't explain the warning:\n%s", md)
+	}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/ssh"
+)
+
+// userState is what we remember about a user between sessions.
+type userState struct {
+	// HideAuthor is inverted so that new users see bylines by default.
+	HideAuthor bool `json:"hide_author,omitempty"`
+	// NoAgeTint turns off coloring titles by how recent they are.
+	NoAgeTint bool `json:"no_age_tint,omitempty"`
+	// QuitMode is what a bare q does, one of quitModes.
+	QuitMode string `json:"quit_mode,omitempty"`
+	// DescLines is how many description lines each item shows: 0 for the
+	// default of one, noDescLines for none.
+	DescLines int `json:"desc_lines,omitempty"`
+	// HideName stops greeting the user by name.
+	HideName bool `json:"hide_name,omitempty"`
+	// GroupByDay puts a header above each day's items.
+	GroupByDay bool `json:"group_by_day,omitempty"`
+	// History lists opened items, oldest first, one entry per item.
+	History []readEntry `json:"history,omitempty"`
+}
+
+// stateStore keeps one JSON file per user in dir. Users are identified by
+// their SSH public key; sessions without one are not persisted.
+//
+// The same user may be connected more than once, each session holding its
+// own copy of their state, so save merges into what is on disk rather than
+// overwriting it; see mergeState.
+//
+// Sessions save off the update loop, so each change is queued first and
+// written after; flush writes whatever is still queued when the server
+// stops.
+type stateStore struct {
+	dir string
+
+	// mu guards locks and queued.
+	mu     sync.Mutex
+	locks  map[string]*sync.Mutex
+	queued map[string]userState
+}
+
+func newStateStore(dir string) (*stateStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &stateStore{dir: dir, locks: map[string]*sync.Mutex{}, queued: map[string]userState{}}, nil
+}
+
+// lock serializes access to one user's file, leaving other users alone.
+func (s *stateStore) lock(user string) *sync.Mutex {
+	s.mu.Lock()
+	l, ok := s.locks[user]
+	if !ok {
+		l = &sync.Mutex{}
+		s.locks[user] = l
+	}
+	s.mu.Unlock()
+	l.Lock()
+	return l
+}
+
+// userKey identifies the user behind a session, or "" for anonymous ones.
+func userKey(key ssh.PublicKey) string {
+	if key == nil {
+		return ""
+	}
+	sum := sha256.Sum256(key.Marshal())
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *stateStore) path(user string) string {
+	return filepath.Join(s.dir, user+".json")
+}
+
+// load returns the saved state for user, or the zero state if there is none.
+func (s *stateStore) load(user string) (userState, error) {
+	if s == nil || user == "" {
+		return userState{}, nil
+	}
+	defer s.lock(user).Unlock()
+	return s.read(user)
+}
+
+// read must be called with the user's lock held.
+func (s *stateStore) read(user string) (userState, error) {
+	var st userState
+	data, err := os.ReadFile(s.path(user))
+	if errors.Is(err, fs.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return st, err
+	}
+	err = json.Unmarshal(data, &st)
+	return st, err
+}
+
+// queue holds st until write or flush saves it. States queued by several
+// sessions of one user are merged like they are on disk.
+func (s *stateStore) queue(user string, st userState) {
+	if s == nil || user == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.queued == nil {
+		s.queued = map[string]userState{}
+	}
+	if prev, ok := s.queued[user]; ok {
+		st = mergeState(prev, st)
+	}
+	s.queued[user] = st
+}
+
+// write saves the user's queued state, if it hasn't been already.
+func (s *stateStore) write(user string) error {
+	if s == nil || user == "" {
+		return nil
+	}
+	defer s.lock(user).Unlock()
+	s.mu.Lock()
+	st, ok := s.queued[user]
+	delete(s.queued, user)
+	s.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return s.persist(user, st)
+}
+
+// flush writes every queued state, for shutdown.
+func (s *stateStore) flush() error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	users := slices.Collect(maps.Keys(s.queued))
+	s.mu.Unlock()
+	var errs []error
+	for _, user := range users {
+		if err := s.write(user); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// save merges st into the user's saved state and writes the result,
+// replacing the file atomically.
+func (s *stateStore) save(user string, st userState) error {
+	if s == nil || user == "" {
+		return nil
+	}
+	defer s.lock(user).Unlock()
+	return s.persist(user, st)
+}
+
+// persist is save with the user's lock already held.
+func (s *stateStore) persist(user string, st userState) error {
+	// An unreadable file is replaced, as it was before merging existed.
+	saved, _ := s.read(user)
+	data, err := json.Marshal(mergeState(saved, st))
+	if err != nil {
+		return err
+	}
+	tmp := s.path(user) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path(user))
+}
+
+// mergeState combines the saved state with a session's. Preferences are a
+// single choice, so the session saving last wins; history is the union of
+// both, so two sessions never lose each other's reads.
+func mergeState(saved, st userState) userState {
+	st.History = mergeHistory(saved.History, st.History)
+	return st
+}
+
+// mergeHistory keeps one entry per item, the most recently opened, oldest
+// first and capped at maxHistory like recordRead.
+func mergeHistory(a, b []readEntry) []readEntry {
+	latest := make(map[string]readEntry, len(a)+len(b))
+	for _, e := range slices.Concat(a, b) {
+		if prev, ok := latest[e.GUID]; !ok || e.Opened.After(prev.Opened) {
+			latest[e.GUID] = e
+		}
+	}
+	h := slices.Collect(maps.Values(latest))
+	slices.SortStableFunc(h, func(x, y readEntry) int {
+		if c := x.Opened.Compare(y.Opened); c != 0 {
+			return c
+		}
+		return strings.Compare(x.GUID, y.GUID)
+	})
+	if len(h) > maxHistory {
+		h = h[len(h)-maxHistory:]
+	}
+	if len(h) == 0 {
+		return nil
+	}
+	return h
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStateStoreConcurrentSessions(t *testing.T) {
+	store, err := newStateStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	const user, sessions, reads = "abc", 4, 25
+	start := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	// Each session starts from the same loaded state and only ever adds
+	// its own reads, saving after each one like the model does.
+	var wg sync.WaitGroup
+	for s := range sessions {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var st userState
+			for r := range reads {
+				st.History = append(st.History, readEntry{
+					GUID:   fmt.Sprintf("s%d-r%d", s, r),
+					Opened: start.Add(time.Duration(r*sessions+s) * time.Second),
+				})
+				st.QuitMode = quitModes[s%len(quitModes)]
+				if err := store.save(user, st); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	st, err := store.load(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(st.History) != sessions*reads {
+		t.Fatalf("got %d history entries, want %d", len(st.History), sessions*reads)
+	}
+	for i := 1; i < len(st.History); i++ {
+		if st.History[i].Opened.Before(st.History[i-1].Opened) {
+			t.Fatalf("history out of order at %d", i)
+		}
+	}
+}
+
+func TestStateStoreFlush(t *testing.T) {
+	store, err := newStateStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := newModel(testFeed(), userState{}, 80, 24)
+	m.store, m.user = store, "abc"
+
+	// The server stops before either save command gets to run.
+	toggle := m.toggleAuthor()
+	read := m.recordRead(m.list.Items()[0].(rssListItem), time.Now())
+	if err := store.flush(); err != nil {
+		t.Fatal(err)
+	}
+	st, err := store.load("abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !st.HideAuthor || len(st.History) != 1 || st.History[0].GUID != "budget-1" {
+		t.Errorf("flushed state = %+v, want the author hidden and one read", st)
+	}
+
+	// Commands that run late find nothing left to write.
+	toggle()
+	read()
+	if st, _ := store.load("abc"); !st.HideAuthor || len(st.History) != 1 {
+		t.Errorf("late saves changed the state to %+v", st)
+	}
+}
+
+func TestMergeHistory(t *testing.T) {
+	at := func(min int) time.Time { return time.Date(2025, 7, 1, 0, min, 0, 0, time.UTC) }
+	saved := []readEntry{{GUID: "a", Opened: at(1)}, {GUID: "b", Opened: at(2)}}
+	session := []readEntry{{GUID: "c", Opened: at(3)}, {GUID: "a", Opened: at(4)}}
+
+	got := mergeHistory(saved, session)
+	want := []string{"b", "c", "a"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want GUIDs %v", got, want)
+	}
+	for i, g := range want {
+		if got[i].GUID != g {
+			t.Errorf("entry %d = %q, want %q", i, got[i].GUID, g)
+		}
+	}
+	if !got[2].Opened.Equal(at(4)) {
+		t.Errorf("a kept %s, want the later open", got[2].Opened)
+	}
+}
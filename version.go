@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// version is the running build, set with -ldflags "-X main.version=v1.2.3".
+var version = "dev"
+
+// latestVersion fetches the newest release from url, which serves either
+// the bare version or a JSON object with a "version" field.
+func latestVersion(url string) (string, error) {
+	resp, err := newFeedClient(url).Get(url)
+	if err != nil {
+		return "", classifyFetchError(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("version endpoint returned %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+	if err != nil {
+		return "", err
+	}
+	body := strings.TrimSpace(string(data))
+	if strings.HasPrefix(body, "{") {
+		var v struct {
+			Version string `json:"version"`
+		}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return "", err
+		}
+		body = v.Version
+	}
+	if body == "" {
+		return "", fmt.Errorf("version endpoint returned no version")
+	}
+	line, _, _ := strings.Cut(body, "\n")
+	return printable(strings.TrimSpace(line)), nil
+}
+
+// newerVersion reports whether latest is a later release than current,
+// comparing dotted numbers with any leading v ignored. Versions that don't
+// parse that way are newer whenever they differ.
+func newerVersion(latest, current string) bool {
+	a, okA := versionParts(latest)
+	b, okB := versionParts(current)
+	if !okA || !okB {
+		return latest != current
+	}
+	for i := range max(len(a), len(b)) {
+		x, y := 0, 0
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			return x > y
+		}
+	}
+	return false
+}
+
+func versionParts(v string) ([]int, bool) {
+	v = strings.TrimPrefix(v, "v")
+	var parts []int
+	for _, p := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, false
+		}
+		parts = append(parts, n)
+	}
+	return parts, true
+}
+
+// checkVersion logs a notice when url reports a newer build. It never
+// updates anything; that is left to the operator.
+func checkVersion(url string) {
+	latest, err := latestVersion(url)
+	if err != nil {
+		log.Warn("Could not check for a newer version", "error", err)
+		return
+	}
+	if newerVersion(latest, version) {
+		log.Info("A newer version is available", "running", version, "available", latest)
+	}
+}
+
+// watchVersion checks at startup and then every interval until ctx is done.
+func watchVersion(ctx context.Context, url string, interval time.Duration) {
+	checkVersion(url)
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			checkVersion(url)
+		}
+	}
+}
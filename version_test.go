@@ -0,0 +1,49 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewerVersion(t *testing.T) {
+	tests := []struct {
+		latest, current string
+		want            bool
+	}{
+		{"v1.2.0", "v1.1.9", true},
+		{"1.10", "v1.9.3", true},
+		{"v1.2", "v1.2.0", false},
+		{"v1.1.0", "v1.2.0", false},
+		{"v1.2.0", "dev", true},
+		{"nightly", "nightly", false},
+	}
+	for _, tt := range tests {
+		if got := newerVersion(tt.latest, tt.current); got != tt.want {
+			t.Errorf("newerVersion(%q, %q) = %v, want %v", tt.latest, tt.current, got, tt.want)
+		}
+	}
+}
+
+func TestLatestVersion(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/text":
+			w.Write([]byte("v1.4.2\n"))
+		case "/json":
+			w.Write([]byte(`{"version": "v1.4.2", "url": "https://example.com/releases"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	for _, path := range []string{"/text", "/json"} {
+		if v, err := latestVersion(srv.URL + path); err != nil || v != "v1.4.2" {
+			t.Errorf("%s: got %q, %v", path, v, err)
+		}
+	}
+	if _, err := latestVersion(srv.URL + "/missing"); err == nil {
+		t.Error("missing endpoint: got nil error")
+	}
+}